@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"bookmarks-backend/internal/config"
+	"bookmarks-backend/internal/db"
+	"bookmarks-backend/internal/services"
+	"bookmarks-backend/internal/utils"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	apply := flag.Bool("apply", false, "write re-normalized URLs and names (default is a dry run)")
+	flag.Parse()
+
+	_ = godotenv.Load()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("config error: %v", err)
+	}
+
+	queryRules, err := utils.ParseQueryParamRules(cfg.URLStripParams, cfg.URLHostParamRules)
+	if err != nil {
+		log.Fatalf("config error: %v", err)
+	}
+	utils.SetQueryParamRules(queryRules)
+
+	ctx := context.Background()
+	pool, err := db.Connect(ctx, cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("database connection error: %v", err)
+	}
+	defer pool.Close()
+
+	bookmarkService := &services.BookmarkService{Pool: pool}
+	report, err := bookmarkService.RenormalizeURLs(ctx, *apply)
+	if err != nil {
+		log.Fatalf("renormalize error: %v", err)
+	}
+
+	for _, change := range report.Changed {
+		fmt.Printf("change %s: %s -> %s\n", change.BookmarkID, change.OldNormalizedURL, change.NewNormalizedURL)
+	}
+	for _, collision := range report.Collisions {
+		fmt.Printf("collision %s: %v\n", collision.NormalizedURL, collision.BookmarkIDs)
+	}
+	fmt.Printf("bookmarks: scanned %d, changed %d, collisions %d, applied %d\n", report.Scanned, len(report.Changed), len(report.Collisions), report.Applied)
+	pending := len(report.Changed)
+
+	tagReports, err := (&services.TagService{Pool: pool}).RenormalizeNames(ctx, *apply)
+	if err != nil {
+		log.Fatalf("renormalize error: %v", err)
+	}
+	categoryReport, err := (&services.CategoryService{Pool: pool}).RenormalizeNames(ctx, *apply)
+	if err != nil {
+		log.Fatalf("renormalize error: %v", err)
+	}
+	for _, nameReport := range append(tagReports, categoryReport) {
+		for _, change := range nameReport.Changed {
+			fmt.Printf("change %s %s: %q -> %q\n", nameReport.Table, change.ID, change.OldName, change.NewName)
+		}
+		for _, collision := range nameReport.Collisions {
+			fmt.Printf("collision %s %q: %v\n", nameReport.Table, collision.Name, collision.IDs)
+		}
+		fmt.Printf("%s: scanned %d, changed %d, collisions %d, applied %d\n", nameReport.Table, nameReport.Scanned, len(nameReport.Changed), len(nameReport.Collisions), nameReport.Applied)
+		pending += len(nameReport.Changed)
+	}
+
+	if !*apply && pending > 0 {
+		fmt.Println("dry run: re-run with -apply to write changes")
+	}
+}
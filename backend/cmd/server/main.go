@@ -10,6 +10,7 @@ import (
 	"bookmarks-backend/internal/db"
 	"bookmarks-backend/internal/handlers"
 	"bookmarks-backend/internal/services"
+	"bookmarks-backend/internal/utils"
 
 	"github.com/joho/godotenv"
 )
@@ -22,6 +23,12 @@ func main() {
 		log.Fatalf("config error: %v", err)
 	}
 
+	queryRules, err := utils.ParseQueryParamRules(cfg.URLStripParams, cfg.URLHostParamRules)
+	if err != nil {
+		log.Fatalf("config error: %v", err)
+	}
+	utils.SetQueryParamRules(queryRules)
+
 	ctx := context.Background()
 	pool, err := db.Connect(ctx, cfg.DatabaseURL)
 	if err != nil {
@@ -36,10 +43,29 @@ func main() {
 		}
 	}
 
-	bookmarkService := &services.BookmarkService{Pool: pool}
+	ruleCache := services.NewRuleCache()
+	ruleCache.Listen(ctx, pool)
+
+	metadataService := &services.MetadataService{
+		Pool:          pool,
+		TTL:           cfg.MetadataCacheTTL,
+		ErrorTTL:      cfg.MetadataErrorTTL,
+		RefreshTitles: cfg.MetadataRefreshTitles,
+		Fetcher:       utils.NewFetchPool(cfg.FetchConcurrency, cfg.FetchHostInterval, cfg.FetchMaxRetries),
+		RuleCache:     ruleCache,
+	}
+	metadataService.StartRefresher(ctx, cfg.MetadataRefreshInterval)
+
+	bookmarkService := &services.BookmarkService{
+		Pool:             pool,
+		Metadata:         metadataService,
+		RuleCache:        ruleCache,
+		ResolveCanonical: cfg.ResolveCanonicalURLs,
+	}
+	bookmarkService.StartTrashPurger(ctx, cfg.TrashRetention, cfg.TrashPurgeInterval)
 	categoryService := &services.CategoryService{Pool: pool}
 	tagService := &services.TagService{Pool: pool}
-	ruleService := &services.RuleService{Pool: pool}
+	ruleService := &services.RuleService{Pool: pool, RuleCache: ruleCache, Metadata: metadataService}
 	settingsService := &services.SettingsService{Pool: pool}
 	importExportService := &services.ImportExportService{Bookmarks: bookmarkService}
 
@@ -3,14 +3,28 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
-	Port           string
-	DatabaseURL    string
-	FrontendURL    string
-	AllowedOrigins []string
+	Port                    string
+	DatabaseURL             string
+	FrontendURL             string
+	AllowedOrigins          []string
+	MetadataCacheTTL        time.Duration
+	MetadataErrorTTL        time.Duration
+	MetadataRefreshInterval time.Duration
+	MetadataRefreshTitles   bool
+	FetchConcurrency        int
+	FetchHostInterval       time.Duration
+	FetchMaxRetries         int
+	ResolveCanonicalURLs    bool
+	URLStripParams          []string
+	URLHostParamRules       string
+	TrashRetention          time.Duration
+	TrashPurgeInterval      time.Duration
 }
 
 func Load() (*Config, error) {
@@ -22,11 +36,66 @@ func Load() (*Config, error) {
 
 	allowedOrigins := parseList(os.Getenv("ALLOWED_ORIGINS"))
 
+	metadataCacheTTL, err := getDuration("METADATA_CACHE_TTL", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	metadataErrorTTL, err := getDuration("METADATA_ERROR_TTL", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	metadataRefreshInterval, err := getDuration("METADATA_REFRESH_INTERVAL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	metadataRefreshTitles, err := getBool("METADATA_REFRESH_TITLES", false)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchConcurrency, err := getInt("METADATA_FETCH_CONCURRENCY", 8)
+	if err != nil {
+		return nil, err
+	}
+	fetchHostInterval, err := getDuration("METADATA_FETCH_HOST_INTERVAL", time.Second)
+	if err != nil {
+		return nil, err
+	}
+	fetchMaxRetries, err := getInt("METADATA_FETCH_RETRIES", 2)
+	if err != nil {
+		return nil, err
+	}
+	resolveCanonicalURLs, err := getBool("RESOLVE_CANONICAL_URLS", false)
+	if err != nil {
+		return nil, err
+	}
+
+	trashRetention, err := getDuration("TRASH_RETENTION", 30*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	trashPurgeInterval, err := getDuration("TRASH_PURGE_INTERVAL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
-		Port:           port,
-		DatabaseURL:    databaseURL,
-		FrontendURL:    getEnv("FRONTEND_URL", "http://localhost:3002"),
-		AllowedOrigins: allowedOrigins,
+		Port:                    port,
+		DatabaseURL:             databaseURL,
+		FrontendURL:             getEnv("FRONTEND_URL", "http://localhost:3002"),
+		AllowedOrigins:          allowedOrigins,
+		MetadataCacheTTL:        metadataCacheTTL,
+		MetadataErrorTTL:        metadataErrorTTL,
+		MetadataRefreshInterval: metadataRefreshInterval,
+		MetadataRefreshTitles:   metadataRefreshTitles,
+		FetchConcurrency:        fetchConcurrency,
+		FetchHostInterval:       fetchHostInterval,
+		FetchMaxRetries:         fetchMaxRetries,
+		ResolveCanonicalURLs:    resolveCanonicalURLs,
+		URLStripParams:          parseList(os.Getenv("URL_STRIP_PARAMS")),
+		URLHostParamRules:       os.Getenv("URL_HOST_PARAM_RULES"),
+		TrashRetention:          trashRetention,
+		TrashPurgeInterval:      trashPurgeInterval,
 	}, nil
 }
 
@@ -52,3 +121,39 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a duration: %w", key, err)
+	}
+	return duration, nil
+}
+
+func getInt(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer: %w", key, err)
+	}
+	return parsed, nil
+}
+
+func getBool(key string, fallback bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s must be a boolean: %w", key, err)
+	}
+	return parsed, nil
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"bookmarks-backend/internal/models"
 	"bookmarks-backend/internal/services"
 	"bookmarks-backend/internal/utils"
 
@@ -19,6 +20,7 @@ type bookmarkRequest struct {
 	Description string   `json:"description"`
 	Category    string   `json:"category"`
 	Tags        []string `json:"tags"`
+	ReadLater   bool     `json:"readLater"`
 }
 
 type bookmarkUpdateRequest struct {
@@ -27,6 +29,27 @@ type bookmarkUpdateRequest struct {
 	Description *string   `json:"description"`
 	Category    *string   `json:"category"`
 	Tags        *[]string `json:"tags"`
+	ReadLater   *bool     `json:"readLater"`
+}
+
+type bulkFilterRequest struct {
+	Query      string   `json:"q"`
+	Category   string   `json:"category"`
+	Categories []string `json:"categories"`
+	Tags       []string `json:"tags"`
+	ReadLater  *bool    `json:"readLater"`
+}
+
+type bulkActionRequest struct {
+	Type     string   `json:"type"`
+	Tags     []string `json:"tags"`
+	Category string   `json:"category"`
+}
+
+type bulkRequest struct {
+	IDs     []string            `json:"ids"`
+	Filters *bulkFilterRequest  `json:"filters"`
+	Actions []bulkActionRequest `json:"actions"`
 }
 
 func RegisterBookmarkRoutes(router *gin.RouterGroup, service *services.BookmarkService) {
@@ -45,8 +68,15 @@ func RegisterBookmarkRoutes(router *gin.RouterGroup, service *services.BookmarkS
 			Description: req.Description,
 			Category:    req.Category,
 			Tags:        req.Tags,
+			ReadLater:   req.ReadLater,
+			Source:      requestSource(ctx),
 		})
 		if err != nil {
+			var rejected *services.RuleRejectedError
+			if errors.As(err, &rejected) {
+				ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "rejectedBy": rejected.RuleName, "reason": rejected.Reason})
+				return
+			}
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
@@ -69,12 +99,22 @@ func RegisterBookmarkRoutes(router *gin.RouterGroup, service *services.BookmarkS
 		if categoryParam != "" {
 			categories = strings.Split(categoryParam, ",")
 		}
+		var readLater *bool
+		if readLaterParam := strings.TrimSpace(ctx.Query("read_later")); readLaterParam != "" {
+			value, err := strconv.ParseBool(readLaterParam)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "read_later must be a boolean"})
+				return
+			}
+			readLater = &value
+		}
 
 		list, err := service.List(ctx, services.BookmarkFilters{
 			Category:   category,
 			Categories: categories,
 			Tags:       tags,
 			Query:      query,
+			ReadLater:  readLater,
 			Page:       page,
 			PageSize:   pageSize,
 		})
@@ -86,6 +126,66 @@ func RegisterBookmarkRoutes(router *gin.RouterGroup, service *services.BookmarkS
 		ctx.JSON(http.StatusOK, list)
 	})
 
+	routes.POST("/batch", func(ctx *gin.Context) {
+		var req []bookmarkRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		source := requestSource(ctx)
+		inputs := make([]services.BookmarkInput, 0, len(req))
+		for _, item := range req {
+			inputs = append(inputs, services.BookmarkInput{
+				URL:         item.URL,
+				Title:       item.Title,
+				Description: item.Description,
+				Category:    item.Category,
+				Tags:        item.Tags,
+				ReadLater:   item.ReadLater,
+				Source:      source,
+			})
+		}
+
+		report, err := service.CreateMany(ctx, inputs)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, report)
+	})
+
+	routes.POST("/bulk", func(ctx *gin.Context) {
+		var req bulkRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		input := services.BulkInput{IDs: req.IDs}
+		if req.Filters != nil {
+			input.Filters = &services.BookmarkFilters{
+				Query:      strings.TrimSpace(req.Filters.Query),
+				Category:   strings.TrimSpace(req.Filters.Category),
+				Categories: req.Filters.Categories,
+				Tags:       req.Filters.Tags,
+				ReadLater:  req.Filters.ReadLater,
+			}
+		}
+		for _, action := range req.Actions {
+			input.Actions = append(input.Actions, services.BulkAction(action))
+		}
+
+		report, err := service.Bulk(ctx, input)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, report)
+	})
+
 	routes.GET("/lookup", func(ctx *gin.Context) {
 		rawURL := strings.TrimSpace(ctx.Query("url"))
 		if rawURL == "" {
@@ -102,30 +202,51 @@ func RegisterBookmarkRoutes(router *gin.RouterGroup, service *services.BookmarkS
 		bookmark, err := service.GetByNormalizedURL(ctx, normalizedURL)
 		if err != nil {
 			if errors.Is(err, pgx.ErrNoRows) {
-				metadata, metaErr := utils.FetchMetadata(ctx, normalizedURL)
+				metadata, metaErr := service.FetchMetadata(ctx, normalizedURL)
 				if metaErr != nil {
 					ctx.JSON(http.StatusBadRequest, gin.H{"error": metaErr.Error()})
 					return
 				}
 
-				category, tags, ruleErr := service.SuggestForURL(ctx, normalizedURL, metadata.Title)
+				duplicate, dupErr := service.FindByMetadata(ctx, normalizedURL, metadata)
+				if dupErr == nil {
+					ctx.JSON(http.StatusOK, lookupFoundResponse(duplicate))
+					return
+				}
+				if !errors.Is(dupErr, pgx.ErrNoRows) {
+					ctx.JSON(http.StatusInternalServerError, gin.H{"error": dupErr.Error()})
+					return
+				}
+
+				evaluation, ruleErr := service.SuggestForURL(ctx, normalizedURL, metadata.Title, metadata)
 				if ruleErr != nil {
 					ctx.JSON(http.StatusInternalServerError, gin.H{"error": ruleErr.Error()})
 					return
 				}
 
+				category := evaluation.Category
+				if category == "" {
+					category = evaluation.SuggestedCategory
+				}
+
 				structuredTags := []gin.H{}
-				for _, tag := range tags {
+				for _, tag := range evaluation.Tags {
 					structuredTags = append(structuredTags, gin.H{"name": tag})
 				}
 
 				ctx.JSON(http.StatusOK, gin.H{
 					"found":         false,
 					"normalizedUrl": normalizedURL,
-					"title":         metadata.Title,
+					"url":           evaluation.URL,
+					"title":         evaluation.Title,
 					"description":   metadata.Description,
 					"category":      category,
 					"tags":          structuredTags,
+					"readLater":     evaluation.ReadLater,
+					"rejected":      evaluation.RejectedBy != nil,
+					"rejectedBy":    evaluation.RejectedBy,
+					"rejectReason":  evaluation.RejectReason,
+					"properties":    metadata.Properties,
 				})
 				return
 			}
@@ -133,15 +254,7 @@ func RegisterBookmarkRoutes(router *gin.RouterGroup, service *services.BookmarkS
 			return
 		}
 
-		ctx.JSON(http.StatusOK, gin.H{
-			"found":         true,
-			"normalizedUrl": bookmark.NormalizedURL,
-			"title":         bookmark.Title,
-			"description":   bookmark.Description,
-			"category":      bookmark.CategoryName,
-			"tags":          bookmark.Tags,
-			"bookmarkId":    bookmark.ID,
-		})
+		ctx.JSON(http.StatusOK, lookupFoundResponse(bookmark))
 	})
 
 	routes.GET(":id", func(ctx *gin.Context) {
@@ -166,20 +279,86 @@ func RegisterBookmarkRoutes(router *gin.RouterGroup, service *services.BookmarkS
 			Description: req.Description,
 			Category:    req.Category,
 			Tags:        req.Tags,
+			ReadLater:   req.ReadLater,
+			Source:      requestSource(ctx),
 		})
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondUpdateError(ctx, err)
 			return
 		}
 
 		ctx.JSON(http.StatusOK, bookmark)
 	})
 
+	routes.GET(":id/history", func(ctx *gin.Context) {
+		versions, err := service.History(ctx, ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, versions)
+	})
+
+	routes.POST(":id/restore/:version", func(ctx *gin.Context) {
+		version, err := strconv.Atoi(ctx.Param("version"))
+		if err != nil || version <= 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "version must be a positive integer"})
+			return
+		}
+
+		var before bool
+		switch ctx.DefaultQuery("state", "after") {
+		case "after":
+		case "before":
+			before = true
+		default:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "state must be before or after"})
+			return
+		}
+
+		bookmark, err := service.RestoreVersion(ctx, ctx.Param("id"), version, before)
+		if err != nil {
+			respondUpdateError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, bookmark)
+	})
+
 	routes.DELETE(":id", func(ctx *gin.Context) {
-		if err := service.Delete(ctx, ctx.Param("id")); err != nil {
+		if err := service.Delete(ctx, ctx.Param("id"), requestSource(ctx)); err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 		ctx.Status(http.StatusNoContent)
 	})
 }
+
+func requestSource(ctx *gin.Context) string {
+	if strings.HasPrefix(ctx.GetHeader("Origin"), "chrome-extension://") {
+		return services.VersionSourceExtension
+	}
+	return services.VersionSourceAPI
+}
+
+func respondUpdateError(ctx *gin.Context, err error) {
+	var conflict *services.URLConflictError
+	if errors.As(err, &conflict) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error(), "conflictId": conflict.BookmarkID, "trashed": conflict.Trashed})
+		return
+	}
+	ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+func lookupFoundResponse(bookmark *models.Bookmark) gin.H {
+	return gin.H{
+		"found":         true,
+		"normalizedUrl": bookmark.NormalizedURL,
+		"canonicalUrl":  bookmark.CanonicalURL,
+		"title":         bookmark.Title,
+		"description":   bookmark.Description,
+		"category":      bookmark.CategoryName,
+		"tags":          bookmark.Tags,
+		"readLater":     bookmark.ReadLater,
+		"bookmarkId":    bookmark.ID,
+	}
+}
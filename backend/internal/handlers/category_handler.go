@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 
 	"bookmarks-backend/internal/services"
 
@@ -12,6 +14,19 @@ type nameRequest struct {
 	Name string `json:"name"`
 }
 
+type labelRequest struct {
+	Name        *string `json:"name"`
+	DisplayName *string `json:"displayName"`
+	Color       *string `json:"color"`
+	Icon        *string `json:"icon"`
+	Description *string `json:"description"`
+}
+
+type mergeRequest struct {
+	TargetID string `json:"targetId"`
+	Target   string `json:"target"`
+}
+
 func RegisterCategoryRoutes(router *gin.RouterGroup, service *services.CategoryService) {
 	routes := router.Group("/categories")
 
@@ -39,12 +54,12 @@ func RegisterCategoryRoutes(router *gin.RouterGroup, service *services.CategoryS
 	})
 
 	routes.PUT(":id", func(ctx *gin.Context) {
-		var req nameRequest
+		var req labelRequest
 		if err := ctx.ShouldBindJSON(&req); err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		category, err := service.Rename(ctx, ctx.Param("id"), req.Name)
+		category, err := service.Update(ctx, ctx.Param("id"), services.LabelUpdateInput(req))
 		if err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
@@ -52,11 +67,39 @@ func RegisterCategoryRoutes(router *gin.RouterGroup, service *services.CategoryS
 		ctx.JSON(http.StatusOK, category)
 	})
 
+	routes.POST(":id/merge", func(ctx *gin.Context) {
+		var req mergeRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		result, err := service.Merge(ctx, ctx.Param("id"), services.CategoryMergeInput(req))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, result)
+	})
+
+	routes.DELETE("/orphans", func(ctx *gin.Context) {
+		names, err := service.DeleteOrphans(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"deleted": len(names), "names": names})
+	})
+
 	routes.DELETE(":id", func(ctx *gin.Context) {
-		if err := service.Delete(ctx, ctx.Param("id")); err != nil {
+		preview, _ := strconv.ParseBool(ctx.DefaultQuery("preview", "false"))
+		impact, err := service.Delete(ctx, ctx.Param("id"), services.DeleteOptions{
+			ReassignTo: strings.TrimSpace(ctx.Query("reassign_to")),
+			Preview:    preview,
+		})
+		if err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		ctx.Status(http.StatusNoContent)
+		ctx.JSON(http.StatusOK, impact)
 	})
 }
@@ -54,6 +54,7 @@ func (router *Router) Register() *gin.Engine {
 
 	api := engine.Group("/api")
 	RegisterBookmarkRoutes(api, router.Bookmarks)
+	RegisterTrashRoutes(api, router.Bookmarks)
 	RegisterCategoryRoutes(api, router.Categories)
 	RegisterTagRoutes(api, router.Tags)
 	RegisterRuleRoutes(api, router.Rules)
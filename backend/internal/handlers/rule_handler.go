@@ -1,21 +1,50 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"bookmarks-backend/internal/models"
 	"bookmarks-backend/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
 type ruleRequest struct {
-	Name          string   `json:"name"`
-	HostPrefix    string   `json:"hostPrefix"`
-	URLPrefix     string   `json:"urlPrefix"`
-	PathPrefix    string   `json:"pathPrefix"`
-	TitleContains string   `json:"titleContains"`
-	Category      string   `json:"category"`
+	Name           string                `json:"name"`
+	HostPrefix     string                `json:"hostPrefix"`
+	URLPrefix      string                `json:"urlPrefix"`
+	PathPrefix     string                `json:"pathPrefix"`
+	TitleContains  string                `json:"titleContains"`
+	Conditions     *models.RuleCondition `json:"conditions"`
+	Priority       *int                  `json:"priority"`
+	Enabled        *bool                 `json:"enabled"`
+	StopProcessing *bool                 `json:"stopProcessing"`
+	Actions        *models.RuleActions   `json:"actions"`
+	Category       string                `json:"category"`
+	Tags           []string              `json:"tags"`
+}
+
+type ruleTestRequest struct {
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	Description   string   `json:"description"`
 	Tags          []string `json:"tags"`
+	FetchMetadata bool     `json:"fetchMetadata"`
+}
+
+type ruleReorderRequest struct {
+	IDs []string `json:"ids"`
+}
+
+type ruleApplyRequest struct {
+	Mode    string `json:"mode"`
+	Preview bool   `json:"preview"`
 }
 
 func RegisterRuleRoutes(router *gin.RouterGroup, service *services.RuleService) {
@@ -44,6 +73,151 @@ func RegisterRuleRoutes(router *gin.RouterGroup, service *services.RuleService)
 		ctx.JSON(http.StatusCreated, rule)
 	})
 
+	routes.GET("/suggestions", func(ctx *gin.Context) {
+		minSupport, _ := strconv.Atoi(ctx.DefaultQuery("min_support", "5"))
+		minConfidence, _ := strconv.ParseFloat(ctx.DefaultQuery("min_confidence", "0.8"), 64)
+		limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+		suggestions, err := service.Suggest(ctx, services.RuleSuggestionOptions{
+			MinSupport:    minSupport,
+			MinConfidence: minConfidence,
+			Limit:         limit,
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, suggestions)
+	})
+
+	routes.GET("/export", func(ctx *gin.Context) {
+		format := strings.ToLower(ctx.DefaultQuery("format", services.RuleFormatYAML))
+		document, err := service.Export(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		content, err := services.EncodeRuleDocument(document, format)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		contentType := "application/yaml; charset=utf-8"
+		if format == services.RuleFormatJSON {
+			contentType = "application/json; charset=utf-8"
+		}
+		filename := fmt.Sprintf("rules-%s.%s", time.Now().Format("2006-01-02-15-04-05"), format)
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+		ctx.Data(http.StatusOK, contentType, content)
+	})
+
+	routes.POST("/import", func(ctx *gin.Context) {
+		body, err := ctx.GetRawData()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		format := strings.ToLower(ctx.Query("format"))
+		if format == "" {
+			format = services.RuleFormatYAML
+			if strings.Contains(ctx.ContentType(), "json") {
+				format = services.RuleFormatJSON
+			}
+		}
+		dryRun, _ := strconv.ParseBool(ctx.DefaultQuery("dry_run", "false"))
+
+		document, err := services.DecodeRuleDocument(body, format)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		report, err := service.Import(ctx, document, services.RuleImportOptions{DryRun: dryRun})
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, report)
+	})
+
+	routes.POST("/test", func(ctx *gin.Context) {
+		var req ruleTestRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		normalizedURL, evaluation, err := service.Test(ctx, services.RuleTestInput(req))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{
+			"normalizedUrl":     normalizedURL,
+			"url":               evaluation.URL,
+			"title":             evaluation.Title,
+			"category":          evaluation.Category,
+			"suggestedCategory": evaluation.SuggestedCategory,
+			"tags":              evaluation.Tags,
+			"removeTags":        evaluation.RemoveTags,
+			"readLater":         evaluation.ReadLater,
+			"rejectedBy":        evaluation.RejectedBy,
+			"rejectReason":      evaluation.RejectReason,
+			"rules":             evaluation.Rules,
+		})
+	})
+
+	routes.POST("/reorder", func(ctx *gin.Context) {
+		var req ruleReorderRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		rules, err := service.Reorder(ctx, req.IDs)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, rules)
+	})
+
+	routes.POST("/apply-all", func(ctx *gin.Context) {
+		var req ruleApplyRequest
+		if err := bindOptionalJSON(ctx, &req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		report, err := service.Apply(ctx, "", services.RuleApplyOptions(req))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, report)
+	})
+
+	routes.POST(":id/apply", func(ctx *gin.Context) {
+		var req ruleApplyRequest
+		if err := bindOptionalJSON(ctx, &req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		report, err := service.Apply(ctx, ctx.Param("id"), services.RuleApplyOptions(req))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, report)
+	})
+
+	routes.GET(":id/matches", func(ctx *gin.Context) {
+		page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+		pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "20"))
+		matches, err := service.Matches(ctx, ctx.Param("id"), page, pageSize)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, matches)
+	})
+
 	routes.PUT(":id", func(ctx *gin.Context) {
 		var req ruleRequest
 		if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -66,3 +240,13 @@ func RegisterRuleRoutes(router *gin.RouterGroup, service *services.RuleService)
 		ctx.Status(http.StatusNoContent)
 	})
 }
+
+func bindOptionalJSON(ctx *gin.Context, target any) error {
+	if ctx.Request.Body == nil || ctx.Request.ContentLength == 0 {
+		return nil
+	}
+	if err := ctx.ShouldBindJSON(target); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
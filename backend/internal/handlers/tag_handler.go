@@ -2,16 +2,32 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 
 	"bookmarks-backend/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
+type aliasRequest struct {
+	Alias string `json:"alias"`
+}
+
 func RegisterTagRoutes(router *gin.RouterGroup, service *services.TagService) {
 	routes := router.Group("/tags")
 
 	routes.GET("", func(ctx *gin.Context) {
+		if ctx.Query("view") == "tree" {
+			tree, err := service.Tree(ctx)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusOK, tree)
+			return
+		}
+
 		tags, err := service.List(ctx)
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -35,12 +51,12 @@ func RegisterTagRoutes(router *gin.RouterGroup, service *services.TagService) {
 	})
 
 	routes.PUT(":id", func(ctx *gin.Context) {
-		var req nameRequest
+		var req labelRequest
 		if err := ctx.ShouldBindJSON(&req); err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		tag, err := service.Rename(ctx, ctx.Param("id"), req.Name)
+		tag, err := service.Update(ctx, ctx.Param("id"), services.LabelUpdateInput(req))
 		if err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
@@ -48,11 +64,70 @@ func RegisterTagRoutes(router *gin.RouterGroup, service *services.TagService) {
 		ctx.JSON(http.StatusOK, tag)
 	})
 
-	routes.DELETE(":id", func(ctx *gin.Context) {
-		if err := service.Delete(ctx, ctx.Param("id")); err != nil {
+	routes.POST(":id/merge", func(ctx *gin.Context) {
+		var req mergeRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		result, err := service.Merge(ctx, ctx.Param("id"), services.TagMergeInput(req))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, result)
+	})
+
+	routes.GET(":id/aliases", func(ctx *gin.Context) {
+		aliases, err := service.ListAliases(ctx, ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, aliases)
+	})
+
+	routes.POST(":id/aliases", func(ctx *gin.Context) {
+		var req aliasRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		alias, err := service.AddAlias(ctx, ctx.Param("id"), req.Alias)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusCreated, alias)
+	})
+
+	routes.DELETE(":id/aliases/:aliasId", func(ctx *gin.Context) {
+		if err := service.DeleteAlias(ctx, ctx.Param("id"), ctx.Param("aliasId")); err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 		ctx.Status(http.StatusNoContent)
 	})
+
+	routes.DELETE("/orphans", func(ctx *gin.Context) {
+		names, err := service.DeleteOrphans(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"deleted": len(names), "names": names})
+	})
+
+	routes.DELETE(":id", func(ctx *gin.Context) {
+		preview, _ := strconv.ParseBool(ctx.DefaultQuery("preview", "false"))
+		impact, err := service.Delete(ctx, ctx.Param("id"), services.DeleteOptions{
+			ReassignTo: strings.TrimSpace(ctx.Query("reassign_to")),
+			Preview:    preview,
+		})
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, impact)
+	})
 }
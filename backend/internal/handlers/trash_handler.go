@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"bookmarks-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterTrashRoutes(router *gin.RouterGroup, service *services.BookmarkService) {
+	routes := router.Group("/trash")
+
+	routes.GET("", func(ctx *gin.Context) {
+		page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+		pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "20"))
+
+		list, err := service.ListTrash(ctx, page, pageSize)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, list)
+	})
+
+	routes.DELETE("", func(ctx *gin.Context) {
+		deleted, err := service.EmptyTrash(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"deleted": deleted})
+	})
+
+	routes.POST(":id/restore", func(ctx *gin.Context) {
+		bookmark, err := service.Restore(ctx, ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, bookmark)
+	})
+
+	routes.DELETE(":id", func(ctx *gin.Context) {
+		if err := service.DeletePermanently(ctx, ctx.Param("id")); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	})
+}
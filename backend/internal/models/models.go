@@ -3,26 +3,62 @@ package models
 import "time"
 
 type Bookmark struct {
-	ID            string    `json:"id"`
-	URL           string    `json:"url"`
-	NormalizedURL string    `json:"normalizedUrl"`
-	Title         string    `json:"title"`
-	Description   string    `json:"description"`
-	CategoryID    *string   `json:"categoryId"`
-	CategoryName  *string   `json:"categoryName"`
-	Tags          []Tag     `json:"tags"`
-	CreatedAt     time.Time `json:"createdAt"`
-	UpdatedAt     time.Time `json:"updatedAt"`
+	ID                string     `json:"id"`
+	URL               string     `json:"url"`
+	NormalizedURL     string     `json:"normalizedUrl"`
+	CanonicalURL      string     `json:"canonicalUrl"`
+	ReadLater         bool       `json:"readLater"`
+	Title             string     `json:"title"`
+	Description       string     `json:"description"`
+	CategoryID        *string    `json:"categoryId"`
+	CategoryName      *string    `json:"categoryName"`
+	Tags              []Tag      `json:"tags"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+	DeletedAt         *time.Time `json:"deletedAt,omitempty"`
+	RestoredFromTrash bool       `json:"restoredFromTrash,omitempty"`
 }
 
 type Tag struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName,omitempty"`
+	Color       string `json:"color,omitempty"`
+	Icon        string `json:"icon,omitempty"`
+	Description string `json:"description,omitempty"`
+	Usage       *Usage `json:"usage,omitempty"`
+}
+
+type Usage struct {
+	BookmarkCount int        `json:"bookmarkCount"`
+	RuleCount     int        `json:"ruleCount"`
+	LastUsedAt    *time.Time `json:"lastUsedAt"`
+}
+
+type TagNode struct {
+	ID            string     `json:"id,omitempty"`
+	Name          string     `json:"name"`
+	Label         string     `json:"label"`
+	BookmarkCount int        `json:"bookmarkCount"`
+	TotalCount    int        `json:"totalCount"`
+	Children      []*TagNode `json:"children"`
+}
+
+type TagAlias struct {
+	ID        string    `json:"id"`
+	TagID     string    `json:"tagId"`
+	Alias     string    `json:"alias"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
 type Category struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName,omitempty"`
+	Color       string `json:"color,omitempty"`
+	Icon        string `json:"icon,omitempty"`
+	Description string `json:"description,omitempty"`
+	Usage       *Usage `json:"usage,omitempty"`
 }
 
 type Pagination struct {
@@ -37,15 +73,80 @@ type BookmarkListResponse struct {
 }
 
 type Rule struct {
-	ID            string    `json:"id"`
-	Name          string    `json:"name"`
-	HostPrefix    string    `json:"hostPrefix"`
-	URLPrefix     string    `json:"urlPrefix"`
-	PathPrefix    string    `json:"pathPrefix"`
-	TitleContains string    `json:"titleContains"`
-	CategoryID    *string   `json:"categoryId"`
-	CategoryName  *string   `json:"categoryName"`
-	Tags          []Tag     `json:"tags"`
-	CreatedAt     time.Time `json:"createdAt"`
-	UpdatedAt     time.Time `json:"updatedAt"`
+	ID             string         `json:"id"`
+	Name           string         `json:"name"`
+	HostPrefix     string         `json:"hostPrefix"`
+	URLPrefix      string         `json:"urlPrefix"`
+	PathPrefix     string         `json:"pathPrefix"`
+	TitleContains  string         `json:"titleContains"`
+	Conditions     *RuleCondition `json:"conditions"`
+	Priority       int            `json:"priority"`
+	Enabled        bool           `json:"enabled"`
+	StopProcessing bool           `json:"stopProcessing"`
+	Actions        *RuleActions   `json:"actions"`
+	CategoryID     *string        `json:"categoryId"`
+	CategoryName   *string        `json:"categoryName"`
+	Tags           []Tag          `json:"tags"`
+	Stats          RuleStats      `json:"stats"`
+	ReviewNote     string         `json:"reviewNote,omitempty"`
+	CreatedAt      time.Time      `json:"createdAt"`
+	UpdatedAt      time.Time      `json:"updatedAt"`
+}
+
+type RuleStats struct {
+	MatchCount        int64      `json:"matchCount"`
+	LastMatchedAt     *time.Time `json:"lastMatchedAt"`
+	AffectedBookmarks int        `json:"affectedBookmarks"`
+}
+
+type RuleMatch struct {
+	BookmarkID string    `json:"bookmarkId"`
+	URL        string    `json:"url"`
+	Title      string    `json:"title"`
+	Source     string    `json:"source"`
+	MatchedAt  time.Time `json:"matchedAt"`
+}
+
+type RuleMatchListResponse struct {
+	Items      []RuleMatch `json:"items"`
+	Pagination Pagination  `json:"pagination"`
+}
+
+type BookmarkSnapshot struct {
+	URL         string   `json:"url"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Category    string   `json:"category"`
+	Tags        []string `json:"tags"`
+	ReadLater   bool     `json:"readLater"`
+	Trashed     bool     `json:"trashed"`
+}
+
+type BookmarkVersion struct {
+	Version   int               `json:"version"`
+	Source    string            `json:"source"`
+	Before    *BookmarkSnapshot `json:"before"`
+	After     BookmarkSnapshot  `json:"after"`
+	Changed   []string          `json:"changed"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+type RuleCondition struct {
+	All    []RuleCondition `json:"all,omitempty" yaml:"all,omitempty"`
+	Any    []RuleCondition `json:"any,omitempty" yaml:"any,omitempty"`
+	Field  string          `json:"field,omitempty" yaml:"field,omitempty"`
+	Op     string          `json:"op,omitempty" yaml:"op,omitempty"`
+	Value  string          `json:"value,omitempty" yaml:"value,omitempty"`
+	Negate bool            `json:"negate,omitempty" yaml:"negate,omitempty"`
+}
+
+type RuleActions struct {
+	RemoveTags   []string `json:"removeTags,omitempty" yaml:"removeTags,omitempty"`
+	TitlePattern string   `json:"titlePattern,omitempty" yaml:"titlePattern,omitempty"`
+	TitleReplace string   `json:"titleReplace,omitempty" yaml:"titleReplace,omitempty"`
+	URLPattern   string   `json:"urlPattern,omitempty" yaml:"urlPattern,omitempty"`
+	URLReplace   string   `json:"urlReplace,omitempty" yaml:"urlReplace,omitempty"`
+	ReadLater    bool     `json:"readLater,omitempty" yaml:"readLater,omitempty"`
+	Reject       bool     `json:"reject,omitempty" yaml:"reject,omitempty"`
+	RejectReason string   `json:"rejectReason,omitempty" yaml:"rejectReason,omitempty"`
 }
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"bookmarks-backend/internal/models"
+	"bookmarks-backend/internal/utils"
+)
+
+const (
+	maxBatchSize = 200
+
+	BatchStatusCreated  = "created"
+	BatchStatusMerged   = "merged"
+	BatchStatusRejected = "rejected"
+	BatchStatusFailed   = "failed"
+)
+
+type BatchResult struct {
+	Index      int              `json:"index"`
+	URL        string           `json:"url"`
+	Status     string           `json:"status"`
+	Bookmark   *models.Bookmark `json:"bookmark,omitempty"`
+	Error      string           `json:"error,omitempty"`
+	RejectedBy string           `json:"rejectedBy,omitempty"`
+}
+
+type BatchReport struct {
+	Created  int           `json:"created"`
+	Merged   int           `json:"merged"`
+	Rejected int           `json:"rejected"`
+	Failed   int           `json:"failed"`
+	Results  []BatchResult `json:"results"`
+}
+
+func (service *BookmarkService) CreateMany(ctx context.Context, inputs []BookmarkInput) (*BatchReport, error) {
+	if len(inputs) == 0 {
+		return nil, errors.New("at least one bookmark is required")
+	}
+	if len(inputs) > maxBatchSize {
+		return nil, fmt.Errorf("batch accepts at most %d bookmarks", maxBatchSize)
+	}
+
+	unique := map[string]struct{}{}
+	pending := []string{}
+	for _, input := range inputs {
+		if input.Title != "" && input.Description != "" && !service.ResolveCanonical {
+			continue
+		}
+		normalizedURL, err := utils.NormalizeURL(input.URL)
+		if err != nil {
+			continue
+		}
+		if _, exists := unique[normalizedURL]; !exists {
+			unique[normalizedURL] = struct{}{}
+			pending = append(pending, normalizedURL)
+		}
+	}
+	fetched := service.PrefetchMetadata(ctx, pending)
+	fetchMetadata := func(ctx context.Context, normalizedURL string) (*utils.Metadata, error) {
+		if metadata, ok := fetched[normalizedURL]; ok {
+			return metadata, nil
+		}
+		if _, attempted := unique[normalizedURL]; attempted {
+			return nil, errors.New("metadata unavailable")
+		}
+		return service.FetchMetadata(ctx, normalizedURL)
+	}
+
+	report := &BatchReport{Results: make([]BatchResult, 0, len(inputs))}
+	for index, input := range inputs {
+		result := BatchResult{Index: index, URL: input.URL}
+		bookmark, merged, err := service.create(ctx, input, fetchMetadata)
+		switch {
+		case err != nil:
+			var rejected *RuleRejectedError
+			if errors.As(err, &rejected) {
+				result.Status = BatchStatusRejected
+				result.RejectedBy = rejected.RuleName
+				report.Rejected++
+			} else {
+				result.Status = BatchStatusFailed
+				report.Failed++
+			}
+			result.Error = err.Error()
+		case merged:
+			result.Status = BatchStatusMerged
+			result.Bookmark = bookmark
+			report.Merged++
+		default:
+			result.Status = BatchStatusCreated
+			result.Bookmark = bookmark
+			report.Created++
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,393 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"bookmarks-backend/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	BulkActionAddTags         = "add_tags"
+	BulkActionRemoveTags      = "remove_tags"
+	BulkActionSetCategory     = "set_category"
+	BulkActionDelete          = "delete"
+	BulkActionRefreshMetadata = "refresh_metadata"
+	BulkActionApplyRules      = "apply_rules"
+)
+
+type BulkAction struct {
+	Type     string
+	Tags     []string
+	Category string
+}
+
+type BulkInput struct {
+	IDs     []string
+	Filters *BookmarkFilters
+	Actions []BulkAction
+}
+
+type BulkActionResult struct {
+	Type     string `json:"type"`
+	Affected int    `json:"affected"`
+}
+
+type BulkReport struct {
+	Matched int                `json:"matched"`
+	Results []BulkActionResult `json:"results"`
+}
+
+type bulkRefresh struct {
+	result         int
+	ids            []string
+	normalizedURLs []string
+}
+
+type bulkRefreshTarget struct {
+	ID            string
+	NormalizedURL string
+	TitleEdited   bool
+}
+
+func (service *BookmarkService) Bulk(ctx context.Context, input BulkInput) (*BulkReport, error) {
+	if err := validateBulkInput(input); err != nil {
+		return nil, err
+	}
+
+	tx, err := service.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	ids, err := service.bulkTargets(ctx, tx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := ids
+	snapshots, err := loadSnapshots(ctx, tx, targets)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &BulkReport{Matched: len(ids), Results: []BulkActionResult{}}
+	refreshes := []bulkRefresh{}
+	for _, action := range input.Actions {
+		affected := 0
+		if action.Type == BulkActionRefreshMetadata {
+			if len(ids) > 0 {
+				normalizedURLs, err := bulkNormalizedURLs(ctx, tx, ids)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", action.Type, err)
+				}
+				refreshes = append(refreshes, bulkRefresh{result: len(report.Results), ids: ids, normalizedURLs: normalizedURLs})
+			}
+			report.Results = append(report.Results, BulkActionResult{Type: action.Type})
+			continue
+		}
+		if len(ids) > 0 {
+			affected, err = service.applyBulkAction(ctx, tx, ids, action)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", action.Type, err)
+			}
+			if action.Type == BulkActionDelete {
+				ids = nil
+			}
+		}
+		report.Results = append(report.Results, BulkActionResult{Type: action.Type, Affected: affected})
+	}
+
+	for _, id := range targets {
+		if err := recordVersion(ctx, tx, id, VersionSourceBulk, snapshots[id]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, refresh := range refreshes {
+		affected, err := service.bulkRefreshMetadata(ctx, refresh.ids, service.RefreshMetadata(ctx, refresh.normalizedURLs))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", BulkActionRefreshMetadata, err)
+		}
+		report.Results[refresh.result].Affected = affected
+	}
+
+	return report, nil
+}
+
+func validateBulkInput(input BulkInput) error {
+	if len(input.IDs) == 0 && input.Filters == nil {
+		return errors.New("ids or filters are required")
+	}
+	if len(input.IDs) > 0 && input.Filters != nil {
+		return errors.New("provide either ids or filters, not both")
+	}
+	for _, id := range input.IDs {
+		if !uuidPattern.MatchString(id) {
+			return fmt.Errorf("invalid bookmark id %q", id)
+		}
+	}
+	if filters := input.Filters; filters != nil && filters.Query == "" && filters.Category == "" && len(filters.Categories) == 0 && len(filters.Tags) == 0 && filters.ReadLater == nil {
+		return errors.New("filters must include at least one criterion")
+	}
+	if len(input.Actions) == 0 {
+		return errors.New("at least one action is required")
+	}
+	for _, action := range input.Actions {
+		switch action.Type {
+		case BulkActionAddTags, BulkActionRemoveTags:
+			if len(normalizeTags(action.Tags)) == 0 {
+				return fmt.Errorf("%s requires tags", action.Type)
+			}
+		case BulkActionSetCategory, BulkActionDelete, BulkActionRefreshMetadata, BulkActionApplyRules:
+		default:
+			return fmt.Errorf("unknown bulk action %q", action.Type)
+		}
+	}
+	return nil
+}
+
+func (service *BookmarkService) bulkTargets(ctx context.Context, tx pgx.Tx, input BulkInput) ([]string, error) {
+	if input.Filters == nil {
+		rows, err := tx.Query(ctx, "SELECT id FROM bookmarks WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL", input.IDs)
+		if err != nil {
+			return nil, err
+		}
+		return pgx.CollectRows(rows, pgx.RowTo[string])
+	}
+
+	whereSQL, args, err := service.filterClauses(ctx, *input.Filters)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tx.Query(ctx, fmt.Sprintf(`
+		SELECT DISTINCT b.id
+		FROM bookmarks b
+		LEFT JOIN categories c ON c.id = b.category_id
+		LEFT JOIN bookmark_tags bt ON bt.bookmark_id = b.id
+		LEFT JOIN tags t ON t.id = bt.tag_id
+		WHERE %s
+	`, whereSQL), args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+func (service *BookmarkService) applyBulkAction(ctx context.Context, tx pgx.Tx, ids []string, action BulkAction) (int, error) {
+	switch action.Type {
+	case BulkActionAddTags:
+		return bulkAddTags(ctx, tx, ids, action.Tags)
+	case BulkActionRemoveTags:
+		return bulkRemoveTags(ctx, tx, ids, action.Tags)
+	case BulkActionSetCategory:
+		return bulkSetCategory(ctx, tx, ids, action.Category)
+	case BulkActionDelete:
+		commandTag, err := tx.Exec(ctx, "UPDATE bookmarks SET deleted_at = NOW() WHERE id = ANY($1) AND deleted_at IS NULL", ids)
+		if err != nil {
+			return 0, err
+		}
+		return int(commandTag.RowsAffected()), nil
+	case BulkActionApplyRules:
+		return service.bulkApplyRules(ctx, tx, ids)
+	}
+	return 0, fmt.Errorf("unknown bulk action %q", action.Type)
+}
+
+func bulkAddTags(ctx context.Context, tx pgx.Tx, ids []string, names []string) (int, error) {
+	tags, err := upsertTags(ctx, tx, normalizeTags(names), names)
+	if err != nil {
+		return 0, err
+	}
+	tagIDs := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+
+	var affected int
+	err = tx.QueryRow(ctx, `
+		WITH inserted AS (
+			INSERT INTO bookmark_tags (bookmark_id, tag_id)
+			SELECT b.id, tag.id
+			FROM bookmarks b
+			CROSS JOIN unnest($2::uuid[]) AS tag(id)
+			WHERE b.id = ANY($1)
+			ON CONFLICT DO NOTHING
+			RETURNING bookmark_id
+		), touched AS (
+			UPDATE bookmarks
+			SET updated_at = NOW()
+			WHERE id IN (SELECT bookmark_id FROM inserted)
+			RETURNING id
+		)
+		SELECT COUNT(*) FROM touched
+	`, ids, tagIDs).Scan(&affected)
+	return affected, err
+}
+
+func bulkRemoveTags(ctx context.Context, tx pgx.Tx, ids []string, names []string) (int, error) {
+	names, err := resolveTagAliases(ctx, tx, normalizeTags(names))
+	if err != nil {
+		return 0, err
+	}
+
+	var affected int
+	err = tx.QueryRow(ctx, `
+		WITH removed AS (
+			DELETE FROM bookmark_tags bt
+			USING tags t
+			WHERE t.id = bt.tag_id AND t.name = ANY($2) AND bt.bookmark_id = ANY($1)
+			RETURNING bt.bookmark_id
+		), touched AS (
+			UPDATE bookmarks
+			SET updated_at = NOW()
+			WHERE id IN (SELECT bookmark_id FROM removed)
+			RETURNING id
+		)
+		SELECT COUNT(*) FROM touched
+	`, ids, names).Scan(&affected)
+	return affected, err
+}
+
+func bulkSetCategory(ctx context.Context, tx pgx.Tx, ids []string, name string) (int, error) {
+	var categoryID *string
+	if cleaned := utils.NormalizeName(name); cleaned != "" {
+		id, err := upsertCategory(ctx, tx, cleaned, name)
+		if err != nil {
+			return 0, err
+		}
+		categoryID = &id
+	}
+
+	commandTag, err := tx.Exec(ctx, `
+		UPDATE bookmarks
+		SET category_id = $2, updated_at = NOW()
+		WHERE id = ANY($1) AND category_id IS DISTINCT FROM $2::uuid
+	`, ids, categoryID)
+	if err != nil {
+		return 0, err
+	}
+	return int(commandTag.RowsAffected()), nil
+}
+
+func bulkNormalizedURLs(ctx context.Context, tx pgx.Tx, ids []string) ([]string, error) {
+	rows, err := tx.Query(ctx, "SELECT DISTINCT normalized_url FROM bookmarks WHERE id = ANY($1)", ids)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+func (service *BookmarkService) bulkRefreshMetadata(ctx context.Context, ids []string, fetched map[string]*utils.Metadata) (int, error) {
+	if len(fetched) == 0 {
+		return 0, nil
+	}
+
+	set, err := service.RuleCache.Rules(ctx, service.Pool)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := service.Pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, normalized_url, title_edited
+		FROM bookmarks
+		WHERE id = ANY($1)
+		FOR UPDATE
+	`, ids)
+	if err != nil {
+		return 0, err
+	}
+	targets := []bulkRefreshTarget{}
+	for rows.Next() {
+		var target bulkRefreshTarget
+		if err := rows.Scan(&target.ID, &target.NormalizedURL, &target.TitleEdited); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		targets = append(targets, target)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	affected := 0
+	for _, target := range targets {
+		metadata, ok := fetched[target.NormalizedURL]
+		if !ok {
+			continue
+		}
+		before, err := loadSnapshot(ctx, tx, target.ID)
+		if err != nil {
+			return 0, err
+		}
+
+		title := before.Title
+		if !target.TitleEdited && strings.TrimSpace(metadata.Title) != "" {
+			title = refreshedTitle(set, target.NormalizedURL, strings.TrimSpace(metadata.Title), before)
+		}
+		description := before.Description
+		if description == "" {
+			description = strings.TrimSpace(metadata.Description)
+		}
+		if title == before.Title && description == before.Description {
+			continue
+		}
+
+		if _, err := tx.Exec(ctx, `
+			UPDATE bookmarks
+			SET title = $2, description = $3, updated_at = NOW()
+			WHERE id = $1
+		`, target.ID, title, description); err != nil {
+			return 0, err
+		}
+		if err := recordVersion(ctx, tx, target.ID, VersionSourceBulk, before); err != nil {
+			return 0, err
+		}
+		affected++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
+
+func (service *BookmarkService) bulkApplyRules(ctx context.Context, tx pgx.Tx, ids []string) (int, error) {
+	set, err := service.RuleCache.Rules(ctx, service.Pool)
+	if err != nil {
+		return 0, err
+	}
+	targets, err := loadRuleTargets(ctx, tx, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	affected := 0
+	for _, target := range targets {
+		change, ok := ruleChangeFor(set.rules, target, RuleApplyModeCategory)
+		if !ok {
+			continue
+		}
+		if err := applyRuleChange(ctx, tx, change, RuleMatchSourceBulk); err != nil {
+			return 0, err
+		}
+		affected++
+	}
+	return affected, nil
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net/url"
 	"strings"
 	"time"
 
@@ -16,7 +15,10 @@ import (
 )
 
 type BookmarkService struct {
-	Pool *pgxpool.Pool
+	Pool             *pgxpool.Pool
+	Metadata         *MetadataService
+	RuleCache        *RuleCache
+	ResolveCanonical bool
 }
 
 type BookmarkInput struct {
@@ -25,6 +27,8 @@ type BookmarkInput struct {
 	Description string
 	Category    string
 	Tags        []string
+	ReadLater   bool
+	Source      string
 }
 
 type BookmarkUpdateInput struct {
@@ -33,6 +37,21 @@ type BookmarkUpdateInput struct {
 	Description *string
 	Category    *string
 	Tags        *[]string
+	ReadLater   *bool
+	Source      string
+	fromTrash   bool
+}
+
+type URLConflictError struct {
+	BookmarkID string
+	Trashed    bool
+}
+
+func (err *URLConflictError) Error() string {
+	if err.Trashed {
+		return "a bookmark in the trash already uses this url; restore it or delete it permanently first"
+	}
+	return "another bookmark already uses this url"
 }
 
 type BookmarkFilters struct {
@@ -40,19 +59,28 @@ type BookmarkFilters struct {
 	Categories []string
 	Tags       []string
 	Query      string
+	ReadLater  *bool
 	Page       int
 	PageSize   int
 }
 
 func (service *BookmarkService) Create(ctx context.Context, input BookmarkInput) (*models.Bookmark, error) {
+	bookmark, _, err := service.create(ctx, input, service.FetchMetadata)
+	return bookmark, err
+}
+
+func (service *BookmarkService) create(ctx context.Context, input BookmarkInput, fetchMetadata func(context.Context, string) (*utils.Metadata, error)) (*models.Bookmark, bool, error) {
+	rawTags := input.Tags
 	normalizedURL, err := utils.NormalizeURL(input.URL)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	if input.Title == "" || input.Description == "" {
-		metadata, err := utils.FetchMetadata(ctx, normalizedURL)
-		if err == nil && metadata != nil {
+	var metadata *utils.Metadata
+	if input.Title == "" || input.Description == "" || service.ResolveCanonical {
+		fetched, err := fetchMetadata(ctx, normalizedURL)
+		if err == nil && fetched != nil {
+			metadata = fetched
 			if input.Title == "" {
 				input.Title = strings.TrimSpace(metadata.Title)
 			}
@@ -64,25 +92,50 @@ func (service *BookmarkService) Create(ctx context.Context, input BookmarkInput)
 
 	input.Title = strings.TrimSpace(input.Title)
 	if input.Title == "" {
-		return nil, errors.New("title is required")
+		return nil, false, errors.New("title is required")
 	}
 
 	input.Description = strings.TrimSpace(input.Description)
 
-	ruleCategory, ruleTags, err := service.matchRules(ctx, normalizedURL, input.Title)
+	input.Tags, err = resolveTagAliases(ctx, service.Pool, normalizeTags(input.Tags))
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	if input.Category == "" {
-		input.Category = ruleCategory
+
+	evaluation, err := service.matchRules(ctx, normalizedURL, input.Title, input.Description, input.Tags, metadata)
+	if err != nil {
+		return nil, false, err
+	}
+	if rejection := evaluation.rejection(); rejection != nil {
+		if err := recordRuleMatches(ctx, service.Pool, evaluation.matchedRuleIDs(), "", RuleMatchSourceCreate); err != nil {
+			return nil, false, err
+		}
+		return nil, false, rejection
+	}
+	canonicalSource := metadata
+	if evaluation.URL != normalizedURL {
+		input.URL = evaluation.URL
+		normalizedURL = evaluation.URL
+		canonicalSource = nil
 	}
-	if len(ruleTags) > 0 {
-		input.Tags = append(input.Tags, ruleTags...)
+	input.Title = evaluation.Title
+	if input.Category == "" {
+		input.Category = evaluation.Category
 	}
+	input.Tags = removeTagNames(normalizeTags(append(input.Tags, evaluation.Tags...)), evaluation.RemoveTags)
+	input.ReadLater = input.ReadLater || evaluation.ReadLater
+	canonicalURL := service.canonicalURL(normalizedURL, canonicalSource)
 
-	existing, err := service.GetByNormalizedURL(ctx, normalizedURL)
+	existing, err := service.findTrashed(ctx, normalizedURL)
 	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
-		return nil, err
+		return nil, false, err
+	}
+	fromTrash := existing != nil
+	if !fromTrash {
+		existing, err = service.FindDuplicate(ctx, normalizedURL, canonicalURL)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, err
+		}
 	}
 	if existing != nil {
 		if input.Title == "" {
@@ -96,14 +149,29 @@ func (service *BookmarkService) Create(ctx context.Context, input BookmarkInput)
 		description := input.Description
 		category := input.Category
 		tags := input.Tags
+		readLater := existing.ReadLater || input.ReadLater
 		url := input.URL
-		return service.Update(ctx, existing.ID, BookmarkUpdateInput{
+		if existing.NormalizedURL != normalizedURL {
+			url = existing.URL
+		}
+		bookmark, err := service.update(ctx, existing.ID, BookmarkUpdateInput{
 			URL:         &url,
 			Title:       &title,
 			Description: &description,
 			Category:    &category,
 			Tags:        &tags,
-		})
+			ReadLater:   &readLater,
+			Source:      input.Source,
+			fromTrash:   fromTrash,
+		}, false)
+		if err != nil {
+			return nil, false, err
+		}
+		bookmark.RestoredFromTrash = fromTrash
+		if err := recordRuleMatches(ctx, service.Pool, evaluation.matchedRuleIDs(), bookmark.ID, RuleMatchSourceCreate); err != nil {
+			return nil, false, err
+		}
+		return bookmark, true, nil
 	}
 
 	categoryName := utils.NormalizeName(input.Category)
@@ -111,16 +179,16 @@ func (service *BookmarkService) Create(ctx context.Context, input BookmarkInput)
 
 	tx, err := service.Pool.Begin(ctx)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	defer tx.Rollback(ctx)
 
 	var categoryID *string
 	var categoryNamePtr *string
 	if categoryName != "" {
-		id, err := upsertCategory(ctx, tx, categoryName)
+		id, err := upsertCategory(ctx, tx, categoryName, input.Category)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 		categoryID = &id
 		categoryNamePtr = &categoryName
@@ -131,33 +199,43 @@ func (service *BookmarkService) Create(ctx context.Context, input BookmarkInput)
 	var updatedAt time.Time
 
 	err = tx.QueryRow(ctx, `
-		INSERT INTO bookmarks (url, normalized_url, title, description, category_id)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO bookmarks (url, normalized_url, canonical_url, title, description, category_id, read_later)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id, created_at, updated_at
-	`, input.URL, normalizedURL, input.Title, input.Description, categoryID).Scan(&bookmarkID, &createdAt, &updatedAt)
+	`, input.URL, normalizedURL, canonicalURL, input.Title, input.Description, categoryID, input.ReadLater).Scan(&bookmarkID, &createdAt, &updatedAt)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	tags, err := upsertTags(ctx, tx, cleanTags)
+	tags, err := upsertTags(ctx, tx, cleanTags, rawTags)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	if len(tags) > 0 {
 		if err := attachTags(ctx, tx, bookmarkID, tags); err != nil {
-			return nil, err
+			return nil, false, err
 		}
 	}
 
+	if err := recordRuleMatches(ctx, tx, evaluation.matchedRuleIDs(), bookmarkID, RuleMatchSourceCreate); err != nil {
+		return nil, false, err
+	}
+
+	if err := recordVersion(ctx, tx, bookmarkID, versionSource(input.Source), nil); err != nil {
+		return nil, false, err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	return &models.Bookmark{
 		ID:            bookmarkID,
 		URL:           input.URL,
 		NormalizedURL: normalizedURL,
+		CanonicalURL:  canonicalURL,
+		ReadLater:     input.ReadLater,
 		Title:         input.Title,
 		Description:   input.Description,
 		CategoryID:    categoryID,
@@ -165,20 +243,20 @@ func (service *BookmarkService) Create(ctx context.Context, input BookmarkInput)
 		Tags:          tags,
 		CreatedAt:     createdAt,
 		UpdatedAt:     updatedAt,
-	}, nil
+	}, false, nil
 }
 
 func (service *BookmarkService) Get(ctx context.Context, id string) (*models.Bookmark, error) {
 	row := service.Pool.QueryRow(ctx, `
 		SELECT b.id, b.url, b.normalized_url, b.title, b.description, b.category_id,
-		c.name, b.created_at, b.updated_at
+		c.name, b.canonical_url, b.read_later, b.created_at, b.updated_at
 		FROM bookmarks b
 		LEFT JOIN categories c ON c.id = b.category_id
-		WHERE b.id = $1
+		WHERE b.id = $1 AND b.deleted_at IS NULL
 	`, id)
 
 	bookmark := models.Bookmark{}
-	if err := row.Scan(&bookmark.ID, &bookmark.URL, &bookmark.NormalizedURL, &bookmark.Title, &bookmark.Description, &bookmark.CategoryID, &bookmark.CategoryName, &bookmark.CreatedAt, &bookmark.UpdatedAt); err != nil {
+	if err := row.Scan(&bookmark.ID, &bookmark.URL, &bookmark.NormalizedURL, &bookmark.Title, &bookmark.Description, &bookmark.CategoryID, &bookmark.CategoryName, &bookmark.CanonicalURL, &bookmark.ReadLater, &bookmark.CreatedAt, &bookmark.UpdatedAt); err != nil {
 		return nil, err
 	}
 
@@ -194,14 +272,14 @@ func (service *BookmarkService) Get(ctx context.Context, id string) (*models.Boo
 func (service *BookmarkService) GetByNormalizedURL(ctx context.Context, normalizedURL string) (*models.Bookmark, error) {
 	row := service.Pool.QueryRow(ctx, `
 		SELECT b.id, b.url, b.normalized_url, b.title, b.description, b.category_id,
-		c.name, b.created_at, b.updated_at
+		c.name, b.canonical_url, b.read_later, b.created_at, b.updated_at
 		FROM bookmarks b
 		LEFT JOIN categories c ON c.id = b.category_id
-		WHERE b.normalized_url = $1
+		WHERE b.normalized_url = $1 AND b.deleted_at IS NULL
 	`, normalizedURL)
 
 	bookmark := models.Bookmark{}
-	if err := row.Scan(&bookmark.ID, &bookmark.URL, &bookmark.NormalizedURL, &bookmark.Title, &bookmark.Description, &bookmark.CategoryID, &bookmark.CategoryName, &bookmark.CreatedAt, &bookmark.UpdatedAt); err != nil {
+	if err := row.Scan(&bookmark.ID, &bookmark.URL, &bookmark.NormalizedURL, &bookmark.Title, &bookmark.Description, &bookmark.CategoryID, &bookmark.CategoryName, &bookmark.CanonicalURL, &bookmark.ReadLater, &bookmark.CreatedAt, &bookmark.UpdatedAt); err != nil {
 		return nil, err
 	}
 
@@ -214,6 +292,47 @@ func (service *BookmarkService) GetByNormalizedURL(ctx context.Context, normaliz
 	return &bookmark, nil
 }
 
+func (service *BookmarkService) findTrashed(ctx context.Context, normalizedURL string) (*models.Bookmark, error) {
+	bookmark := models.Bookmark{}
+	if err := service.Pool.QueryRow(ctx, `
+		SELECT id, url, normalized_url, title, description, read_later
+		FROM bookmarks
+		WHERE normalized_url = $1 AND deleted_at IS NOT NULL
+	`, normalizedURL).Scan(&bookmark.ID, &bookmark.URL, &bookmark.NormalizedURL, &bookmark.Title, &bookmark.Description, &bookmark.ReadLater); err != nil {
+		return nil, err
+	}
+	return &bookmark, nil
+}
+
+func (service *BookmarkService) FindDuplicate(ctx context.Context, normalizedURL string, canonicalURL string) (*models.Bookmark, error) {
+	if canonicalURL == "" || canonicalURL == normalizedURL {
+		return service.GetByNormalizedURL(ctx, normalizedURL)
+	}
+
+	var id string
+	if err := service.Pool.QueryRow(ctx, `
+		SELECT id
+		FROM bookmarks
+		WHERE (normalized_url = $1 OR normalized_url = $2 OR canonical_url = $2) AND deleted_at IS NULL
+		ORDER BY (normalized_url = $1) DESC, created_at ASC
+		LIMIT 1
+	`, normalizedURL, canonicalURL).Scan(&id); err != nil {
+		return nil, err
+	}
+	return service.Get(ctx, id)
+}
+
+func (service *BookmarkService) FindByMetadata(ctx context.Context, normalizedURL string, metadata *utils.Metadata) (*models.Bookmark, error) {
+	return service.FindDuplicate(ctx, normalizedURL, service.canonicalURL(normalizedURL, metadata))
+}
+
+func (service *BookmarkService) canonicalURL(normalizedURL string, metadata *utils.Metadata) string {
+	if !service.ResolveCanonical || metadata == nil {
+		return ""
+	}
+	return utils.ResolveCanonicalURL(normalizedURL, metadata.FinalURL, metadata.CanonicalURL)
+}
+
 func (service *BookmarkService) List(ctx context.Context, filters BookmarkFilters) (*models.BookmarkListResponse, error) {
 	page := max(filters.Page, 1)
 	pageSize := max(filters.PageSize, 1)
@@ -222,31 +341,11 @@ func (service *BookmarkService) List(ctx context.Context, filters BookmarkFilter
 	}
 	offset := (page - 1) * pageSize
 
-	whereClauses := []string{"1=1"}
-	args := []any{}
-
-	if filters.Query != "" {
-		args = append(args, "%"+filters.Query+"%")
-		index := len(args)
-		whereClauses = append(whereClauses, fmt.Sprintf("(b.title ILIKE $%d OR b.description ILIKE $%d OR b.url ILIKE $%d)", index, index, index))
-	}
-	if filters.Category != "" {
-		args = append(args, utils.NormalizeName(filters.Category))
-		whereClauses = append(whereClauses, fmt.Sprintf("c.name = $%d", len(args)))
-	}
-	if len(filters.Categories) > 0 {
-		normalizedCategories := normalizeTags(filters.Categories)
-		args = append(args, normalizedCategories)
-		whereClauses = append(whereClauses, fmt.Sprintf("c.name = ANY($%d)", len(args)))
-	}
-	if len(filters.Tags) > 0 {
-		normalized := normalizeTags(filters.Tags)
-		args = append(args, normalized)
-		whereClauses = append(whereClauses, fmt.Sprintf("t.name = ANY($%d)", len(args)))
+	whereSQL, args, err := service.filterClauses(ctx, filters)
+	if err != nil {
+		return nil, err
 	}
 
-	whereSQL := strings.Join(whereClauses, " AND ")
-
 	countQuery := fmt.Sprintf(`
 		SELECT COUNT(DISTINCT b.id)
 		FROM bookmarks b
@@ -264,7 +363,7 @@ func (service *BookmarkService) List(ctx context.Context, filters BookmarkFilter
 	args = append(args, pageSize, offset)
 	listQuery := fmt.Sprintf(`
 		SELECT DISTINCT b.id, b.url, b.normalized_url, b.title, b.description, b.category_id,
-		c.name, b.created_at, b.updated_at
+		c.name, b.canonical_url, b.read_later, b.created_at, b.updated_at
 		FROM bookmarks b
 		LEFT JOIN categories c ON c.id = b.category_id
 		LEFT JOIN bookmark_tags bt ON bt.bookmark_id = b.id
@@ -283,7 +382,7 @@ func (service *BookmarkService) List(ctx context.Context, filters BookmarkFilter
 	bookmarks := []models.Bookmark{}
 	for rows.Next() {
 		bookmark := models.Bookmark{}
-		if err := rows.Scan(&bookmark.ID, &bookmark.URL, &bookmark.NormalizedURL, &bookmark.Title, &bookmark.Description, &bookmark.CategoryID, &bookmark.CategoryName, &bookmark.CreatedAt, &bookmark.UpdatedAt); err != nil {
+		if err := rows.Scan(&bookmark.ID, &bookmark.URL, &bookmark.NormalizedURL, &bookmark.Title, &bookmark.Description, &bookmark.CategoryID, &bookmark.CategoryName, &bookmark.CanonicalURL, &bookmark.ReadLater, &bookmark.CreatedAt, &bookmark.UpdatedAt); err != nil {
 			return nil, err
 		}
 		bookmarks = append(bookmarks, bookmark)
@@ -307,12 +406,47 @@ func (service *BookmarkService) List(ctx context.Context, filters BookmarkFilter
 	}, nil
 }
 
+func (service *BookmarkService) filterClauses(ctx context.Context, filters BookmarkFilters) (string, []any, error) {
+	whereClauses := []string{"b.deleted_at IS NULL"}
+	args := []any{}
+
+	if filters.Query != "" {
+		args = append(args, "%"+filters.Query+"%")
+		index := len(args)
+		whereClauses = append(whereClauses, fmt.Sprintf("(b.title ILIKE $%d OR b.description ILIKE $%d OR b.url ILIKE $%d)", index, index, index))
+	}
+	if filters.Category != "" {
+		args = append(args, utils.NormalizeName(filters.Category))
+		whereClauses = append(whereClauses, fmt.Sprintf("c.name = $%d", len(args)))
+	}
+	if len(filters.Categories) > 0 {
+		normalizedCategories := normalizeNames(filters.Categories, utils.NormalizeName)
+		args = append(args, normalizedCategories)
+		whereClauses = append(whereClauses, fmt.Sprintf("c.name = ANY($%d)", len(args)))
+	}
+	if filters.ReadLater != nil {
+		args = append(args, *filters.ReadLater)
+		whereClauses = append(whereClauses, fmt.Sprintf("b.read_later = $%d", len(args)))
+	}
+	if len(filters.Tags) > 0 {
+		normalized, err := resolveTagAliases(ctx, service.Pool, normalizeTags(filters.Tags))
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, normalized)
+		whereClauses = append(whereClauses, fmt.Sprintf("EXISTS (SELECT 1 FROM unnest($%d::text[]) AS f(name) WHERE t.name = f.name OR starts_with(t.name, f.name || '/'))", len(args)))
+	}
+
+	return strings.Join(whereClauses, " AND "), args, nil
+}
+
 func (service *BookmarkService) ListAll(ctx context.Context) ([]models.Bookmark, error) {
 	rows, err := service.Pool.Query(ctx, `
 		SELECT b.id, b.url, b.normalized_url, b.title, b.description, b.category_id,
-		c.name, b.created_at, b.updated_at
+		c.name, b.canonical_url, b.read_later, b.created_at, b.updated_at
 		FROM bookmarks b
 		LEFT JOIN categories c ON c.id = b.category_id
+		WHERE b.deleted_at IS NULL
 		ORDER BY b.created_at DESC
 	`)
 	if err != nil {
@@ -323,7 +457,7 @@ func (service *BookmarkService) ListAll(ctx context.Context) ([]models.Bookmark,
 	bookmarks := []models.Bookmark{}
 	for rows.Next() {
 		bookmark := models.Bookmark{}
-		if err := rows.Scan(&bookmark.ID, &bookmark.URL, &bookmark.NormalizedURL, &bookmark.Title, &bookmark.Description, &bookmark.CategoryID, &bookmark.CategoryName, &bookmark.CreatedAt, &bookmark.UpdatedAt); err != nil {
+		if err := rows.Scan(&bookmark.ID, &bookmark.URL, &bookmark.NormalizedURL, &bookmark.Title, &bookmark.Description, &bookmark.CategoryID, &bookmark.CategoryName, &bookmark.CanonicalURL, &bookmark.ReadLater, &bookmark.CreatedAt, &bookmark.UpdatedAt); err != nil {
 			return nil, err
 		}
 		bookmarks = append(bookmarks, bookmark)
@@ -341,7 +475,22 @@ func (service *BookmarkService) ListAll(ctx context.Context) ([]models.Bookmark,
 }
 
 func (service *BookmarkService) Update(ctx context.Context, id string, input BookmarkUpdateInput) (*models.Bookmark, error) {
-	bookmark, err := service.Get(ctx, id)
+	return service.update(ctx, id, input, true)
+}
+
+func (service *BookmarkService) update(ctx context.Context, id string, input BookmarkUpdateInput, manual bool) (*models.Bookmark, error) {
+	tx, err := service.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	bookmark, err := lockBookmark(ctx, tx, id, input.fromTrash)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := loadSnapshot(ctx, tx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -354,26 +503,30 @@ func (service *BookmarkService) Update(ctx context.Context, id string, input Boo
 		if err != nil {
 			return nil, err
 		}
+		if normalizedURL != bookmark.NormalizedURL {
+			if err := checkURLConflict(ctx, tx, id, normalizedURL); err != nil {
+				return nil, err
+			}
+		}
 		bookmark.URL = *input.URL
 		bookmark.NormalizedURL = normalizedURL
 	}
 
+	titleEdited := false
 	if input.Title != nil {
 		cleaned := strings.TrimSpace(*input.Title)
 		if cleaned == "" {
 			return nil, errors.New("title is required")
 		}
+		titleEdited = manual && cleaned != bookmark.Title
 		bookmark.Title = cleaned
 	}
 	if input.Description != nil {
 		bookmark.Description = strings.TrimSpace(*input.Description)
 	}
-
-	tx, err := service.Pool.Begin(ctx)
-	if err != nil {
-		return nil, err
+	if input.ReadLater != nil {
+		bookmark.ReadLater = *input.ReadLater
 	}
-	defer tx.Rollback(ctx)
 
 	var categoryID *string
 	var categoryName *string
@@ -383,7 +536,7 @@ func (service *BookmarkService) Update(ctx context.Context, id string, input Boo
 			categoryID = nil
 			categoryName = nil
 		} else {
-			id, err := upsertCategory(ctx, tx, name)
+			id, err := upsertCategory(ctx, tx, name, *input.Category)
 			if err != nil {
 				return nil, err
 			}
@@ -397,9 +550,11 @@ func (service *BookmarkService) Update(ctx context.Context, id string, input Boo
 
 	_, err = tx.Exec(ctx, `
 		UPDATE bookmarks
-		SET url = $1, normalized_url = $2, title = $3, description = $4, category_id = $5, updated_at = NOW()
+		SET url = $1, normalized_url = $2, title = $3, description = $4, category_id = $5,
+			canonical_url = CASE WHEN normalized_url = $2 THEN canonical_url ELSE '' END,
+			title_edited = title_edited OR $7, read_later = $8, deleted_at = NULL, updated_at = NOW()
 		WHERE id = $6
-	`, bookmark.URL, bookmark.NormalizedURL, bookmark.Title, bookmark.Description, categoryID, id)
+	`, bookmark.URL, bookmark.NormalizedURL, bookmark.Title, bookmark.Description, categoryID, id, titleEdited, bookmark.ReadLater)
 	if err != nil {
 		return nil, err
 	}
@@ -409,7 +564,7 @@ func (service *BookmarkService) Update(ctx context.Context, id string, input Boo
 		if _, err := tx.Exec(ctx, "DELETE FROM bookmark_tags WHERE bookmark_id = $1", id); err != nil {
 			return nil, err
 		}
-		tags, err := upsertTags(ctx, tx, cleanTags)
+		tags, err := upsertTags(ctx, tx, cleanTags, *input.Tags)
 		if err != nil {
 			return nil, err
 		}
@@ -419,6 +574,10 @@ func (service *BookmarkService) Update(ctx context.Context, id string, input Boo
 		bookmark.Tags = tags
 	}
 
+	if err := recordVersion(ctx, tx, id, versionSource(input.Source), before); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
@@ -430,9 +589,63 @@ func (service *BookmarkService) Update(ctx context.Context, id string, input Boo
 	return bookmark, nil
 }
 
-func (service *BookmarkService) Delete(ctx context.Context, id string) error {
-	_, err := service.Pool.Exec(ctx, "DELETE FROM bookmarks WHERE id = $1", id)
-	return err
+func lockBookmark(ctx context.Context, tx pgx.Tx, id string, includeTrashed bool) (*models.Bookmark, error) {
+	bookmark := models.Bookmark{}
+	if err := tx.QueryRow(ctx, `
+		SELECT b.id, b.url, b.normalized_url, b.title, b.description, b.category_id,
+		c.name, b.canonical_url, b.read_later, b.created_at, b.updated_at
+		FROM bookmarks b
+		LEFT JOIN categories c ON c.id = b.category_id
+		WHERE b.id = $1 AND (b.deleted_at IS NULL OR $2)
+		FOR UPDATE OF b
+	`, id, includeTrashed).Scan(&bookmark.ID, &bookmark.URL, &bookmark.NormalizedURL, &bookmark.Title, &bookmark.Description, &bookmark.CategoryID, &bookmark.CategoryName, &bookmark.CanonicalURL, &bookmark.ReadLater, &bookmark.CreatedAt, &bookmark.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	tags, err := queryBookmarkTags(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	bookmark.Tags = tags
+
+	return &bookmark, nil
+}
+
+func checkURLConflict(ctx context.Context, tx pgx.Tx, id string, normalizedURL string) error {
+	var conflict URLConflictError
+	err := tx.QueryRow(ctx, `
+		SELECT id, deleted_at IS NOT NULL
+		FROM bookmarks
+		WHERE normalized_url = $1 AND id <> $2
+	`, normalizedURL, id).Scan(&conflict.BookmarkID, &conflict.Trashed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return &conflict
+}
+
+func (service *BookmarkService) Delete(ctx context.Context, id string, source string) error {
+	tx, err := service.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := loadSnapshot(ctx, tx, id)
+	if err != nil || before == nil || before.Trashed {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "UPDATE bookmarks SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL", id); err != nil {
+		return err
+	}
+	if err := recordVersion(ctx, tx, id, versionSource(source), before); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 func (service *BookmarkService) UpsertFromImport(ctx context.Context, input BookmarkInput) (*models.Bookmark, error) {
@@ -470,10 +683,18 @@ func (service *BookmarkService) UpsertFromImport(ctx context.Context, input Book
 		}
 	}
 
+	var before *models.BookmarkSnapshot
+	if existingID != "" {
+		before, err = loadSnapshot(ctx, tx, existingID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var categoryID *string
 	var categoryNamePtr *string
 	if categoryName != "" {
-		id, err := upsertCategory(ctx, tx, categoryName)
+		id, err := upsertCategory(ctx, tx, categoryName, input.Category)
 		if err != nil {
 			return nil, err
 		}
@@ -489,17 +710,6 @@ func (service *BookmarkService) UpsertFromImport(ctx context.Context, input Book
 	var updatedAt time.Time
 
 	if existingID == "" {
-		if input.Title == "" || input.Description == "" {
-			metadata, err := utils.FetchMetadata(ctx, normalizedURL)
-			if err == nil && metadata != nil {
-				if input.Title == "" {
-					input.Title = strings.TrimSpace(metadata.Title)
-				}
-				if input.Description == "" {
-					input.Description = strings.TrimSpace(metadata.Description)
-				}
-			}
-		}
 		if input.Title == "" {
 			return nil, errors.New("title is required")
 		}
@@ -520,7 +730,8 @@ func (service *BookmarkService) UpsertFromImport(ctx context.Context, input Book
 		}
 		if err := tx.QueryRow(ctx, `
 			UPDATE bookmarks
-			SET url = $1, normalized_url = $2, title = $3, description = $4, category_id = $5, updated_at = NOW()
+			SET url = $1, normalized_url = $2, title = $3, description = $4, category_id = $5,
+				deleted_at = NULL, updated_at = NOW()
 			WHERE id = $6
 			RETURNING created_at, updated_at
 		`, input.URL, normalizedURL, input.Title, input.Description, categoryID, bookmarkID).Scan(&createdAt, &updatedAt); err != nil {
@@ -540,7 +751,7 @@ func (service *BookmarkService) UpsertFromImport(ctx context.Context, input Book
 		mergedTags = unionTags(existingTags, cleanTags)
 	}
 
-	tags, err := upsertTags(ctx, tx, mergedTags)
+	tags, err := upsertTags(ctx, tx, mergedTags, input.Tags)
 	if err != nil {
 		return nil, err
 	}
@@ -551,6 +762,10 @@ func (service *BookmarkService) UpsertFromImport(ctx context.Context, input Book
 		return nil, err
 	}
 
+	if err := recordVersion(ctx, tx, bookmarkID, VersionSourceImport, before); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
@@ -569,9 +784,63 @@ func (service *BookmarkService) UpsertFromImport(ctx context.Context, input Book
 	}, nil
 }
 
-func (service *BookmarkService) fetchTags(ctx context.Context, bookmarkID string) ([]models.Tag, error) {
+func (service *BookmarkService) FetchMetadata(ctx context.Context, normalizedURL string) (*utils.Metadata, error) {
+	if service.Metadata == nil {
+		return utils.FetchMetadata(ctx, normalizedURL)
+	}
+	return service.Metadata.Fetch(ctx, normalizedURL)
+}
+
+func (service *BookmarkService) PrefetchMetadata(ctx context.Context, normalizedURLs []string) map[string]*utils.Metadata {
+	if service.Metadata != nil {
+		return service.Metadata.FetchMany(ctx, normalizedURLs)
+	}
+
+	results := make(map[string]*utils.Metadata, len(normalizedURLs))
+	for _, normalizedURL := range normalizedURLs {
+		if metadata, err := utils.FetchMetadata(ctx, normalizedURL); err == nil && metadata != nil {
+			results[normalizedURL] = metadata
+		}
+	}
+	return results
+}
+
+func (service *BookmarkService) RefreshMetadata(ctx context.Context, normalizedURLs []string) map[string]*utils.Metadata {
+	if service.Metadata != nil {
+		return service.Metadata.RefreshMany(ctx, normalizedURLs)
+	}
+	return service.PrefetchMetadata(ctx, normalizedURLs)
+}
+
+func (service *BookmarkService) existingNormalizedURLs(ctx context.Context, normalizedURLs []string) (map[string]struct{}, error) {
 	rows, err := service.Pool.Query(ctx, `
-		SELECT t.id, t.name
+		SELECT normalized_url
+		FROM bookmarks
+		WHERE normalized_url = ANY($1)
+	`, normalizedURLs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	existing := map[string]struct{}{}
+	for rows.Next() {
+		var normalizedURL string
+		if err := rows.Scan(&normalizedURL); err != nil {
+			return nil, err
+		}
+		existing[normalizedURL] = struct{}{}
+	}
+	return existing, rows.Err()
+}
+
+func (service *BookmarkService) fetchTags(ctx context.Context, bookmarkID string) ([]models.Tag, error) {
+	return queryBookmarkTags(ctx, service.Pool, bookmarkID)
+}
+
+func queryBookmarkTags(ctx context.Context, db tagAliasQuerier, bookmarkID string) ([]models.Tag, error) {
+	rows, err := db.Query(ctx, `
+		SELECT t.id, t.name, t.display_name, t.color, t.icon, t.description
 		FROM tags t
 		INNER JOIN bookmark_tags bt ON bt.tag_id = t.id
 		WHERE bt.bookmark_id = $1
@@ -585,7 +854,7 @@ func (service *BookmarkService) fetchTags(ctx context.Context, bookmarkID string
 	tags := []models.Tag{}
 	for rows.Next() {
 		var tag models.Tag
-		if err := rows.Scan(&tag.ID, &tag.Name); err != nil {
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.DisplayName, &tag.Color, &tag.Icon, &tag.Description); err != nil {
 			return nil, err
 		}
 		tags = append(tags, tag)
@@ -595,10 +864,14 @@ func (service *BookmarkService) fetchTags(ctx context.Context, bookmarkID string
 }
 
 func normalizeTags(tags []string) []string {
+	return normalizeNames(tags, utils.NormalizeTagName)
+}
+
+func normalizeNames(names []string, normalize func(string) string) []string {
 	unique := map[string]struct{}{}
 	result := []string{}
-	for _, tag := range tags {
-		cleaned := utils.NormalizeName(tag)
+	for _, name := range names {
+		cleaned := normalize(name)
 		if cleaned == "" {
 			continue
 		}
@@ -639,42 +912,104 @@ func attachTags(ctx context.Context, tx pgx.Tx, bookmarkID string, tags []models
 	return nil
 }
 
-func upsertCategory(ctx context.Context, tx pgx.Tx, name string) (string, error) {
+func upsertCategory(ctx context.Context, tx pgx.Tx, name string, raw string) (string, error) {
 	var id string
 	if err := tx.QueryRow(ctx, `
-		INSERT INTO categories (name)
-		VALUES ($1)
+		INSERT INTO categories (name, display_name, last_used_at)
+		VALUES ($1, $2, NOW())
 		ON CONFLICT (name)
-		DO UPDATE SET updated_at = NOW()
+		DO UPDATE SET updated_at = NOW(), last_used_at = NOW()
 		RETURNING id
-	`, name).Scan(&id); err != nil {
+	`, name, categoryDisplayName(raw, name)).Scan(&id); err != nil {
 		return "", err
 	}
 	return id, nil
 }
 
-func upsertTags(ctx context.Context, tx pgx.Tx, names []string) ([]models.Tag, error) {
+func upsertTags(ctx context.Context, tx pgx.Tx, names []string, raw []string) ([]models.Tag, error) {
 	if len(names) == 0 {
 		return []models.Tag{}, nil
 	}
 
+	names, err := resolveTagAliases(ctx, tx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	displayNames := map[string]string{}
+	for _, value := range raw {
+		slug := utils.NormalizeTagName(value)
+		if display := tagDisplayName(value, slug); display != "" {
+			if _, exists := displayNames[slug]; !exists {
+				displayNames[slug] = display
+			}
+		}
+	}
+
+	if err := ensureTagAncestors(ctx, tx, names); err != nil {
+		return nil, err
+	}
+
+	seen := map[string]struct{}{}
 	tags := []models.Tag{}
 	for _, name := range names {
 		var id string
 		if err := tx.QueryRow(ctx, `
-			INSERT INTO tags (name)
-			VALUES ($1)
+			INSERT INTO tags (name, display_name, last_used_at)
+			VALUES ($1, $2, NOW())
 			ON CONFLICT (name)
-			DO UPDATE SET updated_at = NOW()
+			DO UPDATE SET updated_at = NOW(), last_used_at = NOW()
 			RETURNING id
-		`, name).Scan(&id); err != nil {
+		`, name, displayNames[name]).Scan(&id); err != nil {
 			return nil, err
 		}
+		if _, exists := seen[id]; exists {
+			continue
+		}
+		seen[id] = struct{}{}
 		tags = append(tags, models.Tag{ID: id, Name: name})
 	}
 	return tags, nil
 }
 
+func ensureTagAncestors(ctx context.Context, tx pgx.Tx, names []string) error {
+	ancestors := []string{}
+	for _, name := range names {
+		ancestors = append(ancestors, utils.TagAncestors(name)...)
+	}
+	if len(ancestors) == 0 {
+		return nil
+	}
+	var alias, target string
+	err := tx.QueryRow(ctx, `
+		SELECT a.alias, t.name
+		FROM tag_aliases a
+		JOIN tags t ON t.id = a.tag_id
+		WHERE a.alias = ANY($1)
+		ORDER BY a.alias ASC
+		LIMIT 1
+	`, ancestors).Scan(&alias, &target)
+	if err == nil {
+		return fmt.Errorf("parent tag %q is an alias of %q; use %q instead", alias, target, target)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO tags (name)
+		SELECT unnest($1::text[])
+		ON CONFLICT (name) DO NOTHING
+	`, ancestors)
+	return err
+}
+
+func versionSource(source string) string {
+	if source == "" {
+		return VersionSourceAPI
+	}
+	return source
+}
+
 func max(value int, fallback int) int {
 	if value <= 0 {
 		return fallback
@@ -682,117 +1017,126 @@ func max(value int, fallback int) int {
 	return value
 }
 
-type ruleMatch struct {
-	ID            string
-	HostPrefix    string
-	URLPrefix     string
-	PathPrefix    string
-	TitleContains string
-	CategoryName  *string
-	Tags          []models.Tag
-}
-
-func (service *BookmarkService) SuggestForURL(ctx context.Context, normalizedURL string, title string) (string, []string, error) {
-	return service.matchRules(ctx, normalizedURL, title)
+func (service *BookmarkService) SuggestForURL(ctx context.Context, normalizedURL string, title string, metadata *utils.Metadata) (*RuleEvaluation, error) {
+	description := ""
+	if metadata != nil {
+		description = metadata.Description
+	}
+	return service.matchRules(ctx, normalizedURL, title, description, nil, metadata)
 }
 
-func (service *BookmarkService) matchRules(ctx context.Context, normalizedURL string, title string) (string, []string, error) {
-	rules, err := service.loadRules(ctx)
+func (service *BookmarkService) matchRules(ctx context.Context, normalizedURL string, title string, description string, tags []string, metadata *utils.Metadata) (*RuleEvaluation, error) {
+	set, err := service.RuleCache.Rules(ctx, service.Pool)
 	if err != nil {
-		return "", nil, err
+		return nil, err
 	}
 
-	parsed, err := url.Parse(normalizedURL)
+	subject, err := newRuleSubject(normalizedURL, title, description, tags)
 	if err != nil {
-		return "", nil, err
+		return nil, err
 	}
 
-	host := strings.ToLower(parsed.Hostname())
-	path := parsed.EscapedPath()
-	pathLower := strings.ToLower(path)
-	urlLower := strings.ToLower(normalizedURL)
-	titleLower := strings.ToLower(title)
+	evaluation := evaluateRules(set.forHost(subject.Host), subject, metadata)
+	return &evaluation, nil
+}
 
-	category := ""
-	mergedTags := []string{}
-	for _, rule := range rules {
-		if rule.HostPrefix != "" && !strings.HasPrefix(host, strings.ToLower(rule.HostPrefix)) {
-			continue
-		}
-		if rule.URLPrefix != "" && !strings.HasPrefix(urlLower, strings.ToLower(rule.URLPrefix)) {
-			continue
-		}
-		if rule.PathPrefix != "" && !strings.HasPrefix(pathLower, strings.ToLower(rule.PathPrefix)) {
-			continue
-		}
-		if rule.TitleContains != "" && !strings.Contains(titleLower, strings.ToLower(rule.TitleContains)) {
-			continue
-		}
+type URLRenormalization struct {
+	BookmarkID       string `json:"bookmarkId"`
+	URL              string `json:"url"`
+	OldNormalizedURL string `json:"oldNormalizedUrl"`
+	NewNormalizedURL string `json:"newNormalizedUrl"`
+}
 
-		if category == "" && rule.CategoryName != nil {
-			category = *rule.CategoryName
-		}
-		for _, tag := range rule.Tags {
-			mergedTags = append(mergedTags, tag.Name)
-		}
-	}
+type URLCollision struct {
+	NormalizedURL string   `json:"normalizedUrl"`
+	BookmarkIDs   []string `json:"bookmarkIds"`
+}
 
-	return category, normalizeTags(mergedTags), nil
+type RenormalizeReport struct {
+	Scanned    int                  `json:"scanned"`
+	Changed    []URLRenormalization `json:"changed"`
+	Collisions []URLCollision       `json:"collisions"`
+	Applied    int                  `json:"applied"`
 }
 
-func (service *BookmarkService) loadRules(ctx context.Context) ([]ruleMatch, error) {
+func (service *BookmarkService) RenormalizeURLs(ctx context.Context, apply bool) (*RenormalizeReport, error) {
 	rows, err := service.Pool.Query(ctx, `
-		SELECT r.id, r.host_prefix, r.url_prefix, r.path_prefix, r.title_contains, c.name
-		FROM rules r
-		LEFT JOIN categories c ON c.id = r.category_id
-		ORDER BY r.created_at ASC
+		SELECT id, url, normalized_url
+		FROM bookmarks
+		ORDER BY created_at ASC
 	`)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	rules := []ruleMatch{}
+	report := &RenormalizeReport{Changed: []URLRenormalization{}, Collisions: []URLCollision{}}
+	candidates := []URLRenormalization{}
 	for rows.Next() {
-		var rule ruleMatch
-		if err := rows.Scan(&rule.ID, &rule.HostPrefix, &rule.URLPrefix, &rule.PathPrefix, &rule.TitleContains, &rule.CategoryName); err != nil {
+		var candidate URLRenormalization
+		if err := rows.Scan(&candidate.BookmarkID, &candidate.URL, &candidate.OldNormalizedURL); err != nil {
+			rows.Close()
 			return nil, err
 		}
-		rules = append(rules, rule)
+		report.Scanned++
+
+		candidate.NewNormalizedURL = candidate.OldNormalizedURL
+		if normalized, err := utils.NormalizeURL(candidate.URL); err == nil {
+			candidate.NewNormalizedURL = normalized
+		}
+		candidates = append(candidates, candidate)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	for index := range rules {
-		tags, err := service.fetchRuleTags(ctx, rules[index].ID)
-		if err != nil {
-			return nil, err
+	ids := make([]string, len(candidates))
+	oldValues := make([]string, len(candidates))
+	newValues := make([]string, len(candidates))
+	for index, candidate := range candidates {
+		ids[index] = candidate.BookmarkID
+		oldValues[index] = candidate.OldNormalizedURL
+		newValues[index] = candidate.NewNormalizedURL
+	}
+	renamed, collisions := planRenames(ids, oldValues, newValues)
+	for _, collision := range collisions {
+		report.Collisions = append(report.Collisions, URLCollision{NormalizedURL: collision.value, BookmarkIDs: collision.ids})
+	}
+	for index, candidate := range candidates {
+		if renamed[index] {
+			report.Changed = append(report.Changed, candidate)
 		}
-		rules[index].Tags = tags
 	}
 
-	return rules, nil
-}
+	if !apply || len(report.Changed) == 0 {
+		return report, nil
+	}
 
-func (service *BookmarkService) fetchRuleTags(ctx context.Context, ruleID string) ([]models.Tag, error) {
-	rows, err := service.Pool.Query(ctx, `
-		SELECT t.id, t.name
-		FROM tags t
-		INNER JOIN rule_tags rt ON rt.tag_id = t.id
-		WHERE rt.rule_id = $1
-		ORDER BY t.name ASC
-	`, ruleID)
+	tx, err := service.Pool.Begin(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	defer tx.Rollback(ctx)
 
-	tags := []models.Tag{}
-	for rows.Next() {
-		var tag models.Tag
-		if err := rows.Scan(&tag.ID, &tag.Name); err != nil {
+	for _, change := range report.Changed {
+		if _, err := tx.Exec(ctx, "UPDATE bookmarks SET normalized_url = 'renormalize:' || id::text WHERE id = $1", change.BookmarkID); err != nil {
+			return nil, err
+		}
+	}
+	for _, change := range report.Changed {
+		if _, err := tx.Exec(ctx, `
+			UPDATE bookmarks
+			SET normalized_url = $1, updated_at = NOW()
+			WHERE id = $2
+		`, change.NewNormalizedURL, change.BookmarkID); err != nil {
 			return nil, err
 		}
-		tags = append(tags, tag)
 	}
 
-	return tags, nil
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	report.Applied = len(report.Changed)
+
+	return report, nil
 }
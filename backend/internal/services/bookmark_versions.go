@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"bookmarks-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	VersionSourceAPI       = "api"
+	VersionSourceExtension = "extension"
+	VersionSourceImport    = "import"
+	VersionSourceRule      = "rule"
+	VersionSourceBulk      = "bulk"
+	VersionSourceRestore   = "restore"
+	VersionSourceRefresh   = "refresh"
+	VersionSourceMerge     = "merge"
+)
+
+type bookmarkVersionQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func loadSnapshot(ctx context.Context, db bookmarkVersionQuerier, bookmarkID string) (*models.BookmarkSnapshot, error) {
+	var snapshot models.BookmarkSnapshot
+	err := db.QueryRow(ctx, `
+		SELECT b.url, b.title, COALESCE(b.description, ''), COALESCE(c.name, ''), b.read_later, b.deleted_at IS NOT NULL,
+		ARRAY(
+			SELECT t.name
+			FROM bookmark_tags bt
+			INNER JOIN tags t ON t.id = bt.tag_id
+			WHERE bt.bookmark_id = b.id
+			ORDER BY t.name ASC
+		)
+		FROM bookmarks b
+		LEFT JOIN categories c ON c.id = b.category_id
+		WHERE b.id = $1
+	`, bookmarkID).Scan(&snapshot.URL, &snapshot.Title, &snapshot.Description, &snapshot.Category, &snapshot.ReadLater, &snapshot.Trashed, &snapshot.Tags)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+func loadSnapshots(ctx context.Context, tx pgx.Tx, bookmarkIDs []string) (map[string]*models.BookmarkSnapshot, error) {
+	snapshots := make(map[string]*models.BookmarkSnapshot, len(bookmarkIDs))
+	for _, id := range bookmarkIDs {
+		snapshot, err := loadSnapshot(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		snapshots[id] = snapshot
+	}
+	return snapshots, nil
+}
+
+func loadAffectedSnapshots(ctx context.Context, tx pgx.Tx, sql string, args ...any) ([]string, map[string]*models.BookmarkSnapshot, error) {
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, nil, err
+	}
+	snapshots, err := loadSnapshots(ctx, tx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ids, snapshots, nil
+}
+
+func recordVersion(ctx context.Context, tx pgx.Tx, bookmarkID string, source string, before *models.BookmarkSnapshot) error {
+	after, err := loadSnapshot(ctx, tx, bookmarkID)
+	if err != nil || after == nil {
+		return err
+	}
+	if before != nil && len(snapshotChanges(before, after)) == 0 {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, "SELECT 1 FROM bookmarks WHERE id = $1 FOR UPDATE", bookmarkID); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO bookmark_versions (bookmark_id, version, source, before, after)
+		SELECT $1::uuid, COALESCE(MAX(version), 0) + 1, $2::text, $3::jsonb, $4::jsonb
+		FROM bookmark_versions
+		WHERE bookmark_id = $1
+	`, bookmarkID, source, before, after)
+	return err
+}
+
+func snapshotChanges(before *models.BookmarkSnapshot, after *models.BookmarkSnapshot) []string {
+	changed := []string{}
+	if before == nil {
+		return changed
+	}
+	if before.URL != after.URL {
+		changed = append(changed, "url")
+	}
+	if before.Title != after.Title {
+		changed = append(changed, "title")
+	}
+	if before.Description != after.Description {
+		changed = append(changed, "description")
+	}
+	if before.Category != after.Category {
+		changed = append(changed, "category")
+	}
+	if !slices.Equal(before.Tags, after.Tags) {
+		changed = append(changed, "tags")
+	}
+	if before.ReadLater != after.ReadLater {
+		changed = append(changed, "readLater")
+	}
+	if before.Trashed != after.Trashed {
+		changed = append(changed, "trashed")
+	}
+	return changed
+}
+
+func (service *BookmarkService) History(ctx context.Context, id string) ([]models.BookmarkVersion, error) {
+	var exists bool
+	if err := service.Pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM bookmarks WHERE id = $1)", id).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.New("bookmark not found")
+	}
+
+	rows, err := service.Pool.Query(ctx, `
+		SELECT version, source, before, after, created_at
+		FROM bookmark_versions
+		WHERE bookmark_id = $1
+		ORDER BY version DESC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []models.BookmarkVersion{}
+	for rows.Next() {
+		var version models.BookmarkVersion
+		if err := rows.Scan(&version.Version, &version.Source, &version.Before, &version.After, &version.CreatedAt); err != nil {
+			return nil, err
+		}
+		version.Changed = snapshotChanges(version.Before, &version.After)
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+func (service *BookmarkService) RestoreVersion(ctx context.Context, id string, version int, before bool) (*models.Bookmark, error) {
+	column := "after"
+	if before {
+		column = "before"
+	}
+
+	var snapshot *models.BookmarkSnapshot
+	if err := service.Pool.QueryRow(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM bookmark_versions
+		WHERE bookmark_id = $1 AND version = $2
+	`, column), id, version).Scan(&snapshot); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("version not found")
+		}
+		return nil, err
+	}
+	if snapshot == nil {
+		return nil, errors.New("version has no earlier state to restore")
+	}
+
+	return service.update(ctx, id, BookmarkUpdateInput{
+		URL:         &snapshot.URL,
+		Title:       &snapshot.Title,
+		Description: &snapshot.Description,
+		Category:    &snapshot.Category,
+		Tags:        &snapshot.Tags,
+		ReadLater:   &snapshot.ReadLater,
+		Source:      VersionSourceRestore,
+	}, true)
+}
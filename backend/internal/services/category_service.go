@@ -3,11 +3,14 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"strings"
 
 	"bookmarks-backend/internal/models"
 	"bookmarks-backend/internal/utils"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -15,11 +18,25 @@ type CategoryService struct {
 	Pool *pgxpool.Pool
 }
 
+type CategoryMergeInput struct {
+	TargetID string
+	Target   string
+}
+
+type CategoryMergeResult struct {
+	Category  models.Category `json:"category"`
+	Bookmarks int64           `json:"bookmarks"`
+	Rules     int64           `json:"rules"`
+}
+
 func (service *CategoryService) List(ctx context.Context) ([]models.Category, error) {
 	rows, err := service.Pool.Query(ctx, `
-		SELECT id, name
-		FROM categories
-		ORDER BY name ASC
+		SELECT c.id, c.name, c.display_name, c.color, c.icon, c.description,
+			(SELECT COUNT(*) FROM bookmarks b WHERE b.category_id = c.id AND b.deleted_at IS NULL),
+			(SELECT COUNT(*) FROM rules r WHERE r.category_id = c.id),
+			c.last_used_at
+		FROM categories c
+		ORDER BY c.name ASC
 	`)
 	if err != nil {
 		return nil, err
@@ -28,8 +45,8 @@ func (service *CategoryService) List(ctx context.Context) ([]models.Category, er
 
 	categories := []models.Category{}
 	for rows.Next() {
-		var category models.Category
-		if err := rows.Scan(&category.ID, &category.Name); err != nil {
+		category := models.Category{Usage: &models.Usage{}}
+		if err := rows.Scan(&category.ID, &category.Name, &category.DisplayName, &category.Color, &category.Icon, &category.Description, &category.Usage.BookmarkCount, &category.Usage.RuleCount, &category.Usage.LastUsedAt); err != nil {
 			return nil, err
 		}
 		categories = append(categories, category)
@@ -46,46 +63,256 @@ func (service *CategoryService) Create(ctx context.Context, name string) (*model
 
 	var category models.Category
 	if err := service.Pool.QueryRow(ctx, `
-		INSERT INTO categories (name)
-		VALUES ($1)
+		INSERT INTO categories (name, display_name)
+		VALUES ($1, $2)
 		ON CONFLICT (name)
-		DO UPDATE SET updated_at = NOW()
-		RETURNING id, name
-	`, cleaned).Scan(&category.ID, &category.Name); err != nil {
+		DO UPDATE SET updated_at = NOW(),
+			display_name = CASE WHEN categories.display_name = '' THEN EXCLUDED.display_name ELSE categories.display_name END
+		RETURNING id, name, display_name, color, icon, description
+	`, cleaned, categoryDisplayName(name, cleaned)).Scan(&category.ID, &category.Name, &category.DisplayName, &category.Color, &category.Icon, &category.Description); err != nil {
 		return nil, err
 	}
 
 	return &category, nil
 }
 
-func (service *CategoryService) Rename(ctx context.Context, id string, name string) (*models.Category, error) {
-	cleaned := utils.NormalizeName(name)
-	if cleaned == "" {
-		return nil, errors.New("name is required")
+func (service *CategoryService) Update(ctx context.Context, id string, input LabelUpdateInput) (*models.Category, error) {
+	input, err := cleanLabelInput(input)
+	if err != nil {
+		return nil, err
 	}
 
-	var category models.Category
-	if err := service.Pool.QueryRow(ctx, `
-		UPDATE categories
-		SET name = $1, updated_at = NOW()
-		WHERE id = $2
-		RETURNING id, name
-	`, cleaned, id).Scan(&category.ID, &category.Name); err != nil {
+	tx, err := service.Pool.Begin(ctx)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback(ctx)
 
-	return &category, nil
+	if input.Name != nil {
+		cleaned := utils.NormalizeName(*input.Name)
+		if cleaned == "" {
+			return nil, errors.New("name is required")
+		}
+		if _, err := tx.Exec(ctx, "UPDATE categories SET name = $1, updated_at = NOW() WHERE id = $2", cleaned, id); err != nil {
+			if isUniqueViolation(err) {
+				return nil, fmt.Errorf("category %q already exists; merge the categories instead", cleaned)
+			}
+			return nil, err
+		}
+		if input.DisplayName == nil {
+			display := categoryDisplayName(*input.Name, cleaned)
+			input.DisplayName = &display
+		}
+	}
+
+	result, err := updateLabel(ctx, tx, "categories", id, input)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return result.category(), nil
 }
 
-func (service *CategoryService) Delete(ctx context.Context, id string) error {
-	commandTag, err := service.Pool.Exec(ctx, "DELETE FROM categories WHERE id = $1", id)
+func (service *CategoryService) Merge(ctx context.Context, id string, input CategoryMergeInput) (*CategoryMergeResult, error) {
+	tx, err := service.Pool.Begin(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if commandTag.RowsAffected() == 0 {
-		return errors.New("category not found")
+	defer tx.Rollback(ctx)
+
+	result, err := mergeCategory(ctx, tx, id, input)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func mergeCategory(ctx context.Context, tx pgx.Tx, id string, input CategoryMergeInput) (*CategoryMergeResult, error) {
+	if err := tx.QueryRow(ctx, "SELECT id FROM categories WHERE id = $1", id).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+
+	result := &CategoryMergeResult{}
+	switch {
+	case input.TargetID != "":
+		if err := tx.QueryRow(ctx, "SELECT id, name FROM categories WHERE id = $1", input.TargetID).Scan(&result.Category.ID, &result.Category.Name); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, errors.New("target category not found")
+			}
+			return nil, err
+		}
+	case utils.NormalizeName(input.Target) != "":
+		name := utils.NormalizeName(input.Target)
+		categoryID, err := upsertCategory(ctx, tx, name, input.Target)
+		if err != nil {
+			return nil, err
+		}
+		result.Category = models.Category{ID: categoryID, Name: name}
+	default:
+		return nil, errors.New("target is required")
+	}
+	if result.Category.ID == id {
+		return nil, errors.New("cannot merge a category into itself")
+	}
+
+	affected, snapshots, err := loadAffectedSnapshots(ctx, tx, "SELECT id FROM bookmarks WHERE category_id = $1 ORDER BY id FOR UPDATE", id)
+	if err != nil {
+		return nil, err
+	}
+
+	commandTag, err := tx.Exec(ctx, "UPDATE bookmarks SET category_id = $2, updated_at = NOW() WHERE category_id = $1", id, result.Category.ID)
+	if err != nil {
+		return nil, err
+	}
+	result.Bookmarks = commandTag.RowsAffected()
+
+	commandTag, err = tx.Exec(ctx, "UPDATE rules SET category_id = $2, updated_at = NOW() WHERE category_id = $1", id, result.Category.ID)
+	if err != nil {
+		return nil, err
+	}
+	result.Rules = commandTag.RowsAffected()
+
+	if _, err := tx.Exec(ctx, "DELETE FROM categories WHERE id = $1", id); err != nil {
+		return nil, err
+	}
+
+	for _, bookmarkID := range affected {
+		if err := recordVersion(ctx, tx, bookmarkID, VersionSourceMerge, snapshots[bookmarkID]); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (service *CategoryService) DeleteOrphans(ctx context.Context) ([]string, error) {
+	rows, err := service.Pool.Query(ctx, `
+		DELETE FROM categories c
+		WHERE NOT EXISTS (SELECT 1 FROM bookmarks b WHERE b.category_id = c.id)
+			AND NOT EXISTS (SELECT 1 FROM rules r WHERE r.category_id = c.id)
+		RETURNING c.name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+func (service *CategoryService) Delete(ctx context.Context, id string, options DeleteOptions) (*DeleteImpact, error) {
+	if options.Preview {
+		impact, err := categoryDeleteImpact(ctx, service.Pool, id)
+		if err != nil {
+			return nil, err
+		}
+		impact.Preview = true
+		if options.ReassignTo != "" {
+			name, err := categoryReassignTarget(ctx, service.Pool, id, options.ReassignTo)
+			if err != nil {
+				return nil, err
+			}
+			impact.ReassignedTo = &name
+		} else {
+			impact.FlaggedRules = int64(len(impact.Rules))
+		}
+		return impact, nil
+	}
+
+	tx, err := service.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	impact, err := categoryDeleteImpact(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.ReassignTo != "" {
+		targetID, target := reassignTarget(options.ReassignTo)
+		result, err := mergeCategory(ctx, tx, id, CategoryMergeInput{TargetID: targetID, Target: target})
+		if err != nil {
+			return nil, err
+		}
+		impact.ReassignedTo = &result.Category.Name
+	} else {
+		impact.FlaggedRules, err = flagRules(ctx, tx, impact.Rules, deletedNote("category", impact.Name))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(ctx, "DELETE FROM categories WHERE id = $1", id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return impact, nil
+}
+
+func categoryDeleteImpact(ctx context.Context, db deleteImpactQuerier, id string) (*DeleteImpact, error) {
+	impact := &DeleteImpact{}
+	if err := db.QueryRow(ctx, "SELECT name, (SELECT COUNT(*) FROM bookmarks WHERE category_id = $1) FROM categories WHERE id = $1", id).Scan(&impact.Name, &impact.Bookmarks); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+	rules, err := loadRuleReferences(ctx, db, `
+		SELECT id, name
+		FROM rules
+		WHERE category_id = $1
+		ORDER BY priority ASC, created_at ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	impact.Rules = rules
+	return impact, nil
+}
+
+func categoryReassignTarget(ctx context.Context, db deleteImpactQuerier, id string, reassignTo string) (string, error) {
+	targetID, target := reassignTarget(reassignTo)
+	var name string
+	if targetID != "" {
+		if err := db.QueryRow(ctx, "SELECT name FROM categories WHERE id = $1", targetID).Scan(&name); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return "", errors.New("target category not found")
+			}
+			return "", err
+		}
+	} else {
+		name = utils.NormalizeName(target)
+		if name == "" {
+			return "", errors.New("target is required")
+		}
+		if err := db.QueryRow(ctx, "SELECT id FROM categories WHERE name = $1", name).Scan(&targetID); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return "", err
+		}
+	}
+	if targetID == id {
+		return "", errors.New("cannot merge a category into itself")
+	}
+	return name, nil
 }
 
 func ValidateCategoryName(name string) error {
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"bookmarks-backend/internal/models"
+	"bookmarks-backend/internal/utils"
 
 	htmlnode "golang.org/x/net/html"
 )
@@ -31,6 +32,10 @@ func (service *ImportExportService) ImportHTML(ctx context.Context, reader io.Re
 		return nil, err
 	}
 
+	if err := service.prefetchMetadata(ctx, parsed); err != nil {
+		return nil, err
+	}
+
 	imported := []models.Bookmark{}
 	for _, entry := range parsed {
 		bookmark, err := service.Bookmarks.UpsertFromImport(ctx, BookmarkInput{
@@ -49,6 +54,51 @@ func (service *ImportExportService) ImportHTML(ctx context.Context, reader io.Re
 	return imported, nil
 }
 
+func (service *ImportExportService) prefetchMetadata(ctx context.Context, entries []ImportedBookmark) error {
+	normalizedByIndex := make(map[int]string, len(entries))
+	candidates := []string{}
+	for index, entry := range entries {
+		if entry.Title != "" && entry.Description != "" {
+			continue
+		}
+		normalizedURL, err := utils.NormalizeURL(entry.URL)
+		if err != nil {
+			continue
+		}
+		normalizedByIndex[index] = normalizedURL
+		candidates = append(candidates, normalizedURL)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	existing, err := service.Bookmarks.existingNormalizedURLs(ctx, candidates)
+	if err != nil {
+		return err
+	}
+	missing := []string{}
+	for _, normalizedURL := range candidates {
+		if _, exists := existing[normalizedURL]; !exists {
+			missing = append(missing, normalizedURL)
+		}
+	}
+
+	fetched := service.Bookmarks.PrefetchMetadata(ctx, missing)
+	for index, normalizedURL := range normalizedByIndex {
+		metadata, ok := fetched[normalizedURL]
+		if !ok {
+			continue
+		}
+		if entries[index].Title == "" {
+			entries[index].Title = strings.TrimSpace(metadata.Title)
+		}
+		if entries[index].Description == "" {
+			entries[index].Description = strings.TrimSpace(metadata.Description)
+		}
+	}
+	return nil
+}
+
 func (service *ImportExportService) ExportHTML(ctx context.Context) (string, error) {
 	bookmarks, err := service.Bookmarks.ListAll(ctx)
 	if err != nil {
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"bookmarks-backend/internal/models"
+	"bookmarks-backend/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	maxDisplayNameLength = 100
+	maxIconLength        = 32
+	maxLabelDescription  = 500
+)
+
+var labelColorPattern = regexp.MustCompile(`^#([0-9a-f]{3}|[0-9a-f]{6})$`)
+
+type LabelUpdateInput struct {
+	Name        *string
+	DisplayName *string
+	Color       *string
+	Icon        *string
+	Description *string
+}
+
+type label struct {
+	ID          string
+	Name        string
+	DisplayName string
+	Color       string
+	Icon        string
+	Description string
+}
+
+func cleanLabelInput(input LabelUpdateInput) (LabelUpdateInput, error) {
+	trim := func(value *string) *string {
+		if value == nil {
+			return nil
+		}
+		trimmed := strings.TrimSpace(*value)
+		return &trimmed
+	}
+	input.DisplayName = trim(input.DisplayName)
+	input.Icon = trim(input.Icon)
+	input.Description = trim(input.Description)
+	if input.Color != nil {
+		color := strings.ToLower(strings.TrimSpace(*input.Color))
+		if color != "" && !labelColorPattern.MatchString(color) {
+			return input, errors.New("color must be a hex value like #3b82f6")
+		}
+		input.Color = &color
+	}
+	if input.DisplayName != nil && utf8.RuneCountInString(*input.DisplayName) > maxDisplayNameLength {
+		return input, fmt.Errorf("display name must be at most %d characters", maxDisplayNameLength)
+	}
+	if input.Icon != nil && utf8.RuneCountInString(*input.Icon) > maxIconLength {
+		return input, fmt.Errorf("icon must be at most %d characters", maxIconLength)
+	}
+	if input.Description != nil && utf8.RuneCountInString(*input.Description) > maxLabelDescription {
+		return input, fmt.Errorf("description must be at most %d characters", maxLabelDescription)
+	}
+	return input, nil
+}
+
+func updateLabel(ctx context.Context, tx pgx.Tx, table string, id string, input LabelUpdateInput) (*label, error) {
+	var result label
+	err := tx.QueryRow(ctx, fmt.Sprintf(`
+		UPDATE %s
+		SET display_name = COALESCE($2, display_name),
+			color = COALESCE($3, color),
+			icon = COALESCE($4, icon),
+			description = COALESCE($5, description),
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING id, name, display_name, color, icon, description
+	`, table), id, input.DisplayName, input.Color, input.Icon, input.Description).Scan(
+		&result.ID, &result.Name, &result.DisplayName, &result.Color, &result.Icon, &result.Description,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func tagDisplayName(raw string, slug string) string {
+	segments := []string{}
+	for _, segment := range strings.Split(raw, "/") {
+		if trimmed := strings.TrimSpace(segment); trimmed != "" {
+			segments = append(segments, trimmed)
+		}
+	}
+	display := strings.Join(segments, "/")
+	if display == slug || utils.NormalizeTagName(display) != slug {
+		return ""
+	}
+	return display
+}
+
+func categoryDisplayName(raw string, slug string) string {
+	display := strings.TrimSpace(raw)
+	if display == slug || utils.NormalizeName(display) != slug {
+		return ""
+	}
+	return display
+}
+
+func (value *label) tag() *models.Tag {
+	return &models.Tag{
+		ID:          value.ID,
+		Name:        value.Name,
+		DisplayName: value.DisplayName,
+		Color:       value.Color,
+		Icon:        value.Icon,
+		Description: value.Description,
+	}
+}
+
+func (value *label) category() *models.Category {
+	return &models.Category{
+		ID:          value.ID,
+		Name:        value.Name,
+		DisplayName: value.DisplayName,
+		Color:       value.Color,
+		Icon:        value.Icon,
+		Description: value.Description,
+	}
+}
@@ -0,0 +1,462 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"bookmarks-backend/internal/models"
+	"bookmarks-backend/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	metadataStatusOK    = "ok"
+	metadataStatusError = "error"
+)
+
+type MetadataService struct {
+	Pool          *pgxpool.Pool
+	TTL           time.Duration
+	ErrorTTL      time.Duration
+	RefreshTitles bool
+	RefreshBatch  int
+	Fetcher       *utils.FetchPool
+	RuleCache     *RuleCache
+}
+
+type cachedMetadata struct {
+	Title        string
+	Description  string
+	Category     string
+	Tags         []string
+	Properties   map[string]string
+	FinalURL     string
+	CanonicalURL string
+	ETag         string
+	LastModified string
+	Status       string
+	StatusCode   int
+	Error        string
+	FetchedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (service *MetadataService) Fetch(ctx context.Context, normalizedURL string) (*utils.Metadata, error) {
+	cached, err := service.getCached(ctx, normalizedURL)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if metadata, ok, err := service.cachedResult(cached, time.Now()); ok {
+		return metadata, err
+	}
+
+	metadata, err := service.refresh(ctx, normalizedURL, cached)
+	if err != nil && cached.usable() {
+		return cached.metadata(), nil
+	}
+	return metadata, err
+}
+
+func (service *MetadataService) cachedResult(cached *cachedMetadata, now time.Time) (*utils.Metadata, bool, error) {
+	if cached == nil {
+		return nil, false, nil
+	}
+	switch cached.Status {
+	case metadataStatusOK:
+		if now.Sub(cached.FetchedAt) < service.ttl() || (cached.Error != "" && now.Sub(cached.UpdatedAt) < service.errorTTL()) {
+			return cached.metadata(), true, nil
+		}
+	case metadataStatusError:
+		if now.Sub(cached.FetchedAt) < service.errorTTL() {
+			return nil, true, errors.New(cached.Error)
+		}
+	}
+	return nil, false, nil
+}
+
+func (service *MetadataService) Cached(ctx context.Context, normalizedURL string) (*utils.Metadata, error) {
+	cached, err := service.getCached(ctx, normalizedURL)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !cached.usable() {
+		return nil, nil
+	}
+	return cached.metadata(), nil
+}
+
+func (service *MetadataService) RefreshStale(ctx context.Context) (int, error) {
+	batch := service.RefreshBatch
+	if batch <= 0 {
+		batch = 50
+	}
+
+	rows, err := service.Pool.Query(ctx, `
+		SELECT b.normalized_url
+		FROM bookmarks b
+		LEFT JOIN page_metadata pm ON pm.normalized_url = b.normalized_url
+		WHERE b.deleted_at IS NULL
+		AND (pm.normalized_url IS NULL OR pm.fetched_at < $1)
+		AND (pm.normalized_url IS NULL OR pm.status <> $3 OR pm.error = '' OR pm.updated_at < $4)
+		ORDER BY pm.fetched_at ASC NULLS FIRST
+		LIMIT $2
+	`, time.Now().Add(-service.ttl()), batch, metadataStatusOK, time.Now().Add(-service.errorTTL()))
+	if err != nil {
+		return 0, err
+	}
+	urls := []string{}
+	for rows.Next() {
+		var normalizedURL string
+		if err := rows.Scan(&normalizedURL); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		urls = append(urls, normalizedURL)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var refreshed atomic.Int64
+	var firstErr error
+	var errOnce sync.Once
+	service.forEach(ctx, urls, func(normalizedURL string) {
+		cached, err := service.getCached(ctx, normalizedURL)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			errOnce.Do(func() { firstErr = err })
+			return
+		}
+		metadata, err := service.refresh(ctx, normalizedURL, cached)
+		if err != nil {
+			return
+		}
+		refreshed.Add(1)
+		if service.RefreshTitles {
+			if err := service.updateBookmarkTitle(ctx, normalizedURL, metadata.Title); err != nil {
+				errOnce.Do(func() { firstErr = err })
+			}
+		}
+	})
+
+	return int(refreshed.Load()), firstErr
+}
+
+func (service *MetadataService) FetchMany(ctx context.Context, normalizedURLs []string) map[string]*utils.Metadata {
+	var mu sync.Mutex
+	results := make(map[string]*utils.Metadata, len(normalizedURLs))
+	service.forEach(ctx, normalizedURLs, func(normalizedURL string) {
+		metadata, err := service.Fetch(ctx, normalizedURL)
+		if err != nil || metadata == nil {
+			return
+		}
+		mu.Lock()
+		results[normalizedURL] = metadata
+		mu.Unlock()
+	})
+	return results
+}
+
+func (service *MetadataService) RefreshMany(ctx context.Context, normalizedURLs []string) map[string]*utils.Metadata {
+	var mu sync.Mutex
+	results := make(map[string]*utils.Metadata, len(normalizedURLs))
+	service.forEach(ctx, normalizedURLs, func(normalizedURL string) {
+		cached, err := service.getCached(ctx, normalizedURL)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return
+		}
+		metadata, err := service.refresh(ctx, normalizedURL, cached)
+		if err != nil || metadata == nil {
+			return
+		}
+		mu.Lock()
+		results[normalizedURL] = metadata
+		mu.Unlock()
+	})
+	return results
+}
+
+func (service *MetadataService) StartRefresher(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				refreshed, err := service.RefreshStale(ctx)
+				if err != nil {
+					log.Printf("metadata refresh error: %v", err)
+					continue
+				}
+				if refreshed > 0 {
+					log.Printf("metadata refresh: %d entries updated", refreshed)
+				}
+			}
+		}
+	}()
+}
+
+func (service *MetadataService) refresh(ctx context.Context, normalizedURL string, cached *cachedMetadata) (*utils.Metadata, error) {
+	metadata, fetchErr := service.fetch(ctx, normalizedURL, cached.conditional())
+	if fetchErr != nil {
+		statusCode := 0
+		if metadata != nil {
+			statusCode = metadata.StatusCode
+		}
+		failed := failedRefresh(cached, statusCode, fetchErr, time.Now())
+		if failed.Status == metadataStatusOK {
+			if _, err := service.Pool.Exec(ctx, `
+				UPDATE page_metadata
+				SET status_code = $1, error = $2, updated_at = NOW()
+				WHERE normalized_url = $3
+			`, failed.StatusCode, failed.Error, normalizedURL); err != nil {
+				return nil, err
+			}
+			return nil, fetchErr
+		}
+		if _, err := service.Pool.Exec(ctx, `
+			INSERT INTO page_metadata (normalized_url, status, status_code, error, fetched_at)
+			VALUES ($1, $2, $3, $4, NOW())
+			ON CONFLICT (normalized_url)
+			DO UPDATE SET status = EXCLUDED.status, status_code = EXCLUDED.status_code,
+				error = EXCLUDED.error, fetched_at = NOW(), updated_at = NOW()
+		`, normalizedURL, failed.Status, failed.StatusCode, failed.Error); err != nil {
+			return nil, err
+		}
+		return nil, fetchErr
+	}
+
+	if metadata.NotModified && cached != nil {
+		if _, err := service.Pool.Exec(ctx, `
+			UPDATE page_metadata
+			SET status = $1, status_code = $2, error = '', fetched_at = NOW(), updated_at = NOW()
+			WHERE normalized_url = $3
+		`, metadataStatusOK, metadata.StatusCode, normalizedURL); err != nil {
+			return nil, err
+		}
+		fresh := cached.metadata()
+		fresh.ETag = metadata.ETag
+		fresh.LastModified = metadata.LastModified
+		fresh.StatusCode = metadata.StatusCode
+		fresh.NotModified = true
+		return fresh, nil
+	}
+
+	metadata.Title = strings.TrimSpace(metadata.Title)
+	metadata.Description = strings.TrimSpace(metadata.Description)
+	if metadata.Tags == nil {
+		metadata.Tags = []string{}
+	}
+	if metadata.Properties == nil {
+		metadata.Properties = map[string]string{}
+	}
+	if _, err := service.Pool.Exec(ctx, `
+		INSERT INTO page_metadata (normalized_url, title, description, category, tags, properties,
+			final_url, canonical_url, etag, last_modified, status, status_code, error, fetched_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, '', NOW())
+		ON CONFLICT (normalized_url)
+		DO UPDATE SET title = EXCLUDED.title, description = EXCLUDED.description,
+			category = EXCLUDED.category, tags = EXCLUDED.tags, properties = EXCLUDED.properties,
+			final_url = EXCLUDED.final_url, canonical_url = EXCLUDED.canonical_url,
+			etag = EXCLUDED.etag, last_modified = EXCLUDED.last_modified,
+			status = EXCLUDED.status, status_code = EXCLUDED.status_code,
+			error = '', fetched_at = NOW(), updated_at = NOW()
+	`, normalizedURL, metadata.Title, metadata.Description, metadata.Category, metadata.Tags, metadata.Properties,
+		metadata.FinalURL, metadata.CanonicalURL, metadata.ETag, metadata.LastModified, metadataStatusOK, metadata.StatusCode); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+func failedRefresh(cached *cachedMetadata, statusCode int, fetchErr error, now time.Time) *cachedMetadata {
+	if cached.usable() {
+		failed := *cached
+		failed.StatusCode = statusCode
+		failed.Error = fetchErr.Error()
+		failed.UpdatedAt = now
+		return &failed
+	}
+	return &cachedMetadata{
+		Status:     metadataStatusError,
+		StatusCode: statusCode,
+		Error:      fetchErr.Error(),
+		FetchedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+func (service *MetadataService) fetch(ctx context.Context, normalizedURL string, conditional utils.ConditionalHeaders) (*utils.Metadata, error) {
+	if service.Fetcher == nil {
+		return utils.FetchMetadataConditional(ctx, normalizedURL, conditional)
+	}
+	return service.Fetcher.Fetch(ctx, normalizedURL, conditional)
+}
+
+func (service *MetadataService) forEach(ctx context.Context, normalizedURLs []string, fn func(normalizedURL string)) {
+	workers := 1
+	if service.Fetcher != nil {
+		workers = service.Fetcher.Size()
+	}
+
+	queue := make(chan string)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for normalizedURL := range queue {
+				fn(normalizedURL)
+			}
+		}()
+	}
+
+	seen := map[string]struct{}{}
+	for _, normalizedURL := range utils.InterleaveByHost(normalizedURLs) {
+		if _, exists := seen[normalizedURL]; exists {
+			continue
+		}
+		seen[normalizedURL] = struct{}{}
+		select {
+		case queue <- normalizedURL:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(queue)
+	wg.Wait()
+}
+
+func (service *MetadataService) getCached(ctx context.Context, normalizedURL string) (*cachedMetadata, error) {
+	var cached cachedMetadata
+	if err := service.Pool.QueryRow(ctx, `
+		SELECT title, description, category, tags, properties, final_url, canonical_url,
+		etag, last_modified, status, status_code, error, fetched_at, updated_at
+		FROM page_metadata
+		WHERE normalized_url = $1
+	`, normalizedURL).Scan(&cached.Title, &cached.Description, &cached.Category, &cached.Tags, &cached.Properties,
+		&cached.FinalURL, &cached.CanonicalURL, &cached.ETag, &cached.LastModified, &cached.Status, &cached.StatusCode,
+		&cached.Error, &cached.FetchedAt, &cached.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &cached, nil
+}
+
+func (cached *cachedMetadata) usable() bool {
+	return cached != nil && cached.Status == metadataStatusOK
+}
+
+func (cached *cachedMetadata) conditional() utils.ConditionalHeaders {
+	if !cached.usable() {
+		return utils.ConditionalHeaders{}
+	}
+	return utils.ConditionalHeaders{ETag: cached.ETag, LastModified: cached.LastModified}
+}
+
+func (cached *cachedMetadata) metadata() *utils.Metadata {
+	return &utils.Metadata{
+		Title:        cached.Title,
+		Description:  cached.Description,
+		Category:     cached.Category,
+		Tags:         cached.Tags,
+		Properties:   cached.Properties,
+		FinalURL:     cached.FinalURL,
+		CanonicalURL: cached.CanonicalURL,
+		ETag:         cached.ETag,
+		LastModified: cached.LastModified,
+	}
+}
+
+func (service *MetadataService) updateBookmarkTitle(ctx context.Context, normalizedURL string, title string) error {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil
+	}
+
+	set, err := service.RuleCache.Rules(ctx, service.Pool)
+	if err != nil {
+		return err
+	}
+
+	tx, err := service.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT id
+		FROM bookmarks
+		WHERE normalized_url = $1 AND deleted_at IS NULL AND title_edited = FALSE
+		FOR UPDATE
+	`, normalizedURL)
+	if err != nil {
+		return err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		before, err := loadSnapshot(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		rewritten := refreshedTitle(set, normalizedURL, title, before)
+		if rewritten == before.Title {
+			continue
+		}
+		if _, err := tx.Exec(ctx, "UPDATE bookmarks SET title = $1, updated_at = NOW() WHERE id = $2", rewritten, id); err != nil {
+			return err
+		}
+		if err := recordVersion(ctx, tx, id, VersionSourceRefresh, before); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func refreshedTitle(set *ruleSet, normalizedURL string, title string, snapshot *models.BookmarkSnapshot) string {
+	subject, err := newRuleSubject(normalizedURL, title, snapshot.Description, snapshot.Tags)
+	if err != nil {
+		return title
+	}
+	if rewritten := strings.TrimSpace(evaluateRules(set.forHost(subject.Host), subject, nil).Title); rewritten != "" {
+		return rewritten
+	}
+	return title
+}
+
+func (service *MetadataService) errorTTL() time.Duration {
+	if service.ErrorTTL <= 0 {
+		return 15 * time.Minute
+	}
+	return service.ErrorTTL
+}
+
+func (service *MetadataService) ttl() time.Duration {
+	if service.TTL <= 0 {
+		return 24 * time.Hour
+	}
+	return service.TTL
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMetadataFailedRefreshKeepsServingGoodData(t *testing.T) {
+	service := &MetadataService{TTL: time.Hour, ErrorTTL: 15 * time.Minute}
+	now := time.Now()
+	fetchErr := errors.New("metadata fetch failed: 503")
+
+	tests := []struct {
+		name        string
+		cached      *cachedMetadata
+		wantStatus  string
+		wantServed  bool
+		wantTitle   string
+		wantErr     bool
+		wantETag    string
+		wantRetried bool
+	}{
+		{
+			name: "ok entry then failed refresh is still served",
+			cached: &cachedMetadata{
+				Title:        "Go",
+				Description:  "The Go programming language",
+				ETag:         `"v1"`,
+				LastModified: "Mon, 02 Jan 2026 15:04:05 GMT",
+				Status:       metadataStatusOK,
+				StatusCode:   200,
+				FetchedAt:    now.Add(-2 * time.Hour),
+				UpdatedAt:    now.Add(-2 * time.Hour),
+			},
+			wantStatus:  metadataStatusOK,
+			wantServed:  true,
+			wantTitle:   "Go",
+			wantETag:    `"v1"`,
+			wantRetried: true,
+		},
+		{
+			name:        "failed refresh without prior data is an error",
+			cached:      nil,
+			wantStatus:  metadataStatusError,
+			wantServed:  true,
+			wantErr:     true,
+			wantRetried: true,
+		},
+		{
+			name: "failed refresh after an earlier error stays an error",
+			cached: &cachedMetadata{
+				Status:    metadataStatusError,
+				Error:     "timeout",
+				FetchedAt: now.Add(-time.Hour),
+				UpdatedAt: now.Add(-time.Hour),
+			},
+			wantStatus:  metadataStatusError,
+			wantServed:  true,
+			wantErr:     true,
+			wantRetried: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, ok, _ := service.cachedResult(test.cached, now); ok {
+				t.Fatal("stale entry was served without a refresh")
+			}
+
+			failed := failedRefresh(test.cached, 503, fetchErr, now)
+			if failed.Status != test.wantStatus {
+				t.Errorf("status = %q, want %q", failed.Status, test.wantStatus)
+			}
+			if failed.StatusCode != 503 || failed.Error != fetchErr.Error() {
+				t.Errorf("failure recorded as %d %q, want 503 %q", failed.StatusCode, failed.Error, fetchErr.Error())
+			}
+
+			metadata, ok, err := service.cachedResult(failed, now.Add(time.Minute))
+			if ok != test.wantServed {
+				t.Fatalf("served from cache = %v, want %v", ok, test.wantServed)
+			}
+			if (err != nil) != test.wantErr {
+				t.Fatalf("cachedResult error = %v, want error %v", err, test.wantErr)
+			}
+			if !test.wantErr && (metadata == nil || metadata.Title != test.wantTitle) {
+				t.Errorf("cachedResult metadata = %+v, want title %q", metadata, test.wantTitle)
+			}
+			if got := failed.conditional().ETag; got != test.wantETag {
+				t.Errorf("conditional ETag = %q, want %q", got, test.wantETag)
+			}
+
+			if _, ok, _ := service.cachedResult(failed, now.Add(service.ErrorTTL+time.Minute)); ok == test.wantRetried {
+				t.Errorf("served after error ttl = %v, want retry %v", ok, test.wantRetried)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"bookmarks-backend/internal/utils"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type NameRenormalization struct {
+	ID      string `json:"id"`
+	OldName string `json:"oldName"`
+	NewName string `json:"newName"`
+}
+
+type NameCollision struct {
+	Name string   `json:"name"`
+	IDs  []string `json:"ids"`
+}
+
+type NameRenormalizeReport struct {
+	Table      string                `json:"table"`
+	Scanned    int                   `json:"scanned"`
+	Changed    []NameRenormalization `json:"changed"`
+	Collisions []NameCollision       `json:"collisions"`
+	Applied    int                   `json:"applied"`
+}
+
+func (service *TagService) RenormalizeNames(ctx context.Context, apply bool) ([]*NameRenormalizeReport, error) {
+	tags, err := renormalizeNames(ctx, service.Pool, "tags", "name", utils.NormalizeTagName, apply)
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := renormalizeNames(ctx, service.Pool, "tag_aliases", "alias", utils.NormalizeTagName, apply)
+	if err != nil {
+		return nil, err
+	}
+	return []*NameRenormalizeReport{tags, aliases}, nil
+}
+
+func (service *CategoryService) RenormalizeNames(ctx context.Context, apply bool) (*NameRenormalizeReport, error) {
+	return renormalizeNames(ctx, service.Pool, "categories", "name", utils.NormalizeName, apply)
+}
+
+func renormalizeNames(ctx context.Context, pool *pgxpool.Pool, table string, column string, normalize func(string) string, apply bool) (*NameRenormalizeReport, error) {
+	rows, err := pool.Query(ctx, fmt.Sprintf("SELECT id, %s FROM %s ORDER BY created_at ASC", column, table))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &NameRenormalizeReport{Table: table, Changed: []NameRenormalization{}, Collisions: []NameCollision{}}
+	candidates := []NameRenormalization{}
+	for rows.Next() {
+		var candidate NameRenormalization
+		if err := rows.Scan(&candidate.ID, &candidate.OldName); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		report.Scanned++
+
+		candidate.NewName = normalize(candidate.OldName)
+		if candidate.NewName == "" {
+			candidate.NewName = candidate.OldName
+		}
+		candidates = append(candidates, candidate)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(candidates))
+	oldValues := make([]string, len(candidates))
+	newValues := make([]string, len(candidates))
+	for index, candidate := range candidates {
+		ids[index] = candidate.ID
+		oldValues[index] = candidate.OldName
+		newValues[index] = candidate.NewName
+	}
+	renamed, collisions := planRenames(ids, oldValues, newValues)
+	for _, collision := range collisions {
+		report.Collisions = append(report.Collisions, NameCollision{Name: collision.value, IDs: collision.ids})
+	}
+	for index, candidate := range candidates {
+		if renamed[index] {
+			report.Changed = append(report.Changed, candidate)
+		}
+	}
+
+	if !apply || len(report.Changed) == 0 {
+		return report, nil
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, change := range report.Changed {
+		if _, err := tx.Exec(ctx, fmt.Sprintf("UPDATE %s SET %s = 'renormalize:' || id::text WHERE id = $1", table, column), change.ID); err != nil {
+			return nil, err
+		}
+	}
+	for _, change := range report.Changed {
+		if _, err := tx.Exec(ctx, fmt.Sprintf("UPDATE %s SET %s = $1 WHERE id = $2", table, column), change.NewName, change.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	report.Applied = len(report.Changed)
+
+	return report, nil
+}
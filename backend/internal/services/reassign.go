@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+type deleteImpactQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type DeleteOptions struct {
+	ReassignTo string
+	Preview    bool
+}
+
+type RuleReference struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type DeleteImpact struct {
+	Preview      bool            `json:"preview"`
+	Name         string          `json:"name"`
+	Bookmarks    int             `json:"bookmarks"`
+	Rules        []RuleReference `json:"rules"`
+	ReassignedTo *string         `json:"reassignedTo"`
+	FlaggedRules int64           `json:"flaggedRules"`
+}
+
+func reassignTarget(value string) (string, string) {
+	if uuidPattern.MatchString(value) {
+		return value, ""
+	}
+	return "", value
+}
+
+func loadRuleReferences(ctx context.Context, db deleteImpactQuerier, sql string, id string) ([]RuleReference, error) {
+	rows, err := db.Query(ctx, sql, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []RuleReference{}
+	for rows.Next() {
+		var rule RuleReference
+		if err := rows.Scan(&rule.ID, &rule.Name); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func flagRules(ctx context.Context, tx pgx.Tx, rules []RuleReference, note string) (int64, error) {
+	if len(rules) == 0 {
+		return 0, nil
+	}
+	ids := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		ids = append(ids, rule.ID)
+	}
+	commandTag, err := tx.Exec(ctx, "UPDATE rules SET review_note = $2 WHERE id = ANY($1)", ids, note)
+	if err != nil {
+		return 0, err
+	}
+	return commandTag.RowsAffected(), nil
+}
+
+func deletedNote(kind string, name string) string {
+	return fmt.Sprintf("%s %q was deleted", kind, name)
+}
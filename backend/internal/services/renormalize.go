@@ -0,0 +1,61 @@
+package services
+
+import "slices"
+
+type renameCollision struct {
+	value string
+	ids   []string
+}
+
+func planRenames(ids []string, oldValues []string, newValues []string) ([]bool, []renameCollision) {
+	renamed := make([]bool, len(ids))
+	for index := range ids {
+		renamed[index] = newValues[index] != oldValues[index]
+	}
+
+	collisions := []renameCollision{}
+	collisionIndex := map[string]int{}
+	for {
+		holders := map[string][]int{}
+		order := []string{}
+		for index := range ids {
+			value := oldValues[index]
+			if renamed[index] {
+				value = newValues[index]
+			}
+			if _, exists := holders[value]; !exists {
+				order = append(order, value)
+			}
+			holders[value] = append(holders[value], index)
+		}
+
+		reverted := false
+		for _, value := range order {
+			indexes := holders[value]
+			if len(indexes) < 2 {
+				continue
+			}
+			collided := []string{}
+			for _, index := range indexes {
+				collided = append(collided, ids[index])
+				if renamed[index] {
+					renamed[index] = false
+					reverted = true
+				}
+			}
+			if existing, ok := collisionIndex[value]; ok {
+				for _, id := range collided {
+					if !slices.Contains(collisions[existing].ids, id) {
+						collisions[existing].ids = append(collisions[existing].ids, id)
+					}
+				}
+				continue
+			}
+			collisionIndex[value] = len(collisions)
+			collisions = append(collisions, renameCollision{value: value, ids: collided})
+		}
+		if !reverted {
+			return renamed, collisions
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestPlanRenames(t *testing.T) {
+	tests := []struct {
+		name           string
+		ids            []string
+		oldValues      []string
+		newValues      []string
+		wantRenamed    []bool
+		wantCollisions []renameCollision
+	}{
+		{
+			name:           "nothing to rename",
+			ids:            []string{"a", "b"},
+			oldValues:      []string{"go", "rust"},
+			newValues:      []string{"go", "rust"},
+			wantRenamed:    []bool{false, false},
+			wantCollisions: []renameCollision{},
+		},
+		{
+			name:           "independent renames",
+			ids:            []string{"a", "b"},
+			oldValues:      []string{"Go", "Rust"},
+			newValues:      []string{"go", "rust"},
+			wantRenamed:    []bool{true, true},
+			wantCollisions: []renameCollision{},
+		},
+		{
+			name:           "two renames onto the same value",
+			ids:            []string{"a", "b", "c"},
+			oldValues:      []string{"Go", "GO", "Rust"},
+			newValues:      []string{"go", "go", "rust"},
+			wantRenamed:    []bool{false, false, true},
+			wantCollisions: []renameCollision{{value: "go", ids: []string{"a", "b"}}},
+		},
+		{
+			name:           "rename onto an unchanged value",
+			ids:            []string{"a", "b"},
+			oldValues:      []string{"Go", "go"},
+			newValues:      []string{"go", "go"},
+			wantRenamed:    []bool{false, false},
+			wantCollisions: []renameCollision{{value: "go", ids: []string{"a", "b"}}},
+		},
+		{
+			name:        "reverting a rename exposes a chained collision",
+			ids:         []string{"a", "b", "c"},
+			oldValues:   []string{"p", "q", "r"},
+			newValues:   []string{"q", "r", "r"},
+			wantRenamed: []bool{false, false, false},
+			wantCollisions: []renameCollision{
+				{value: "r", ids: []string{"b", "c"}},
+				{value: "q", ids: []string{"a", "b"}},
+			},
+		},
+		{
+			name:        "reverted renames cascade into another collision",
+			ids:         []string{"a", "b", "c", "d"},
+			oldValues:   []string{"x", "X", "y", "Y"},
+			newValues:   []string{"y", "y", "y", "x"},
+			wantRenamed: []bool{false, false, false, false},
+			wantCollisions: []renameCollision{
+				{value: "y", ids: []string{"a", "b", "c"}},
+				{value: "x", ids: []string{"a", "d"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			renamed, collisions := planRenames(test.ids, test.oldValues, test.newValues)
+			if !slices.Equal(renamed, test.wantRenamed) {
+				t.Errorf("renamed = %v, want %v", renamed, test.wantRenamed)
+			}
+			if !reflect.DeepEqual(collisions, test.wantCollisions) {
+				t.Errorf("collisions = %+v, want %+v", collisions, test.wantCollisions)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"bookmarks-backend/internal/utils"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const ruleCacheChannel = "rules_changed"
+
+type RuleCache struct {
+	mu         sync.Mutex
+	set        *ruleSet
+	generation uint64
+}
+
+type ruleSet struct {
+	rules     []ruleMatch
+	hosts     *hostTrie
+	unindexed []int
+}
+
+type hostTrie struct {
+	children map[byte]*hostTrie
+	rules    []int
+}
+
+func NewRuleCache() *RuleCache {
+	return &RuleCache{}
+}
+
+func (cache *RuleCache) Rules(ctx context.Context, pool *pgxpool.Pool) (*ruleSet, error) {
+	if cache == nil {
+		return buildRuleSet(ctx, pool)
+	}
+
+	cache.mu.Lock()
+	set := cache.set
+	generation := cache.generation
+	cache.mu.Unlock()
+	if set != nil {
+		return set, nil
+	}
+
+	set, err := buildRuleSet(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.mu.Lock()
+	if cache.generation == generation {
+		cache.set = set
+	}
+	cache.mu.Unlock()
+	return set, nil
+}
+
+func (cache *RuleCache) Invalidate() {
+	if cache == nil {
+		return
+	}
+	cache.mu.Lock()
+	cache.set = nil
+	cache.generation++
+	cache.mu.Unlock()
+}
+
+func (cache *RuleCache) Listen(ctx context.Context, pool *pgxpool.Pool) {
+	go func() {
+		for {
+			if err := cache.listen(ctx, pool); err != nil && ctx.Err() == nil {
+				log.Printf("rule cache listener error: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+		}
+	}()
+}
+
+func (cache *RuleCache) listen(ctx context.Context, pool *pgxpool.Pool) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+ruleCacheChannel); err != nil {
+		return err
+	}
+	cache.Invalidate()
+
+	for {
+		if _, err := conn.Conn().WaitForNotification(ctx); err != nil {
+			conn.Conn().Close(context.Background())
+			return err
+		}
+		cache.Invalidate()
+	}
+}
+
+func buildRuleSet(ctx context.Context, pool *pgxpool.Pool) (*ruleSet, error) {
+	rules, err := loadRuleMatches(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+	return newRuleSet(rules), nil
+}
+
+func newRuleSet(rules []ruleMatch) *ruleSet {
+	set := &ruleSet{rules: rules, hosts: &hostTrie{}}
+	for index, rule := range rules {
+		prefix, ok := rule.Condition.hostPrefix()
+		if !ok {
+			set.unindexed = append(set.unindexed, index)
+			continue
+		}
+		set.hosts.insert(prefix, index)
+	}
+	return set
+}
+
+func (set *ruleSet) explain(subject ruleSubject, metadata *utils.Metadata) RuleEvaluation {
+	return evaluateRules(set.rules, subject, metadata)
+}
+
+func (set *ruleSet) forHost(host string) []ruleMatch {
+	matched := make([]bool, len(set.rules))
+	for _, index := range set.unindexed {
+		matched[index] = true
+	}
+	for _, index := range set.hosts.lookup(host) {
+		matched[index] = true
+	}
+
+	candidates := []ruleMatch{}
+	for index, rule := range set.rules {
+		if !matched[index] {
+			continue
+		}
+		if rule.Actions.URLPattern != nil {
+			return set.rules
+		}
+		candidates = append(candidates, rule)
+	}
+	return candidates
+}
+
+func (condition compiledCondition) hostPrefix() (string, bool) {
+	if condition.Group != "all" || condition.Negate {
+		return "", false
+	}
+	for _, child := range condition.Children {
+		if child.Group == "" && child.Field == "host" && !child.Negate && (child.Op == "prefix" || child.Op == "equals") {
+			return child.Value, true
+		}
+	}
+	return "", false
+}
+
+func (node *hostTrie) insert(prefix string, index int) {
+	for i := 0; i < len(prefix); i++ {
+		if node.children == nil {
+			node.children = map[byte]*hostTrie{}
+		}
+		child := node.children[prefix[i]]
+		if child == nil {
+			child = &hostTrie{}
+			node.children[prefix[i]] = child
+		}
+		node = child
+	}
+	node.rules = append(node.rules, index)
+}
+
+func (node *hostTrie) lookup(host string) []int {
+	indexes := append([]int{}, node.rules...)
+	for i := 0; i < len(host) && node != nil; i++ {
+		node = node.children[host[i]]
+		if node != nil {
+			indexes = append(indexes, node.rules...)
+		}
+	}
+	return indexes
+}
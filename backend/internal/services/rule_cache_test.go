@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+
+	"bookmarks-backend/internal/models"
+)
+
+func TestRuleSetExplainIncludesRulesForOtherHosts(t *testing.T) {
+	set := newRuleSet([]ruleMatch{
+		testRule("code", "Code", nil, models.RuleCondition{Field: "host", Op: "equals", Value: "github.com"}, nil),
+		testRule("news", "News", nil, models.RuleCondition{Field: "host", Op: "prefix", Value: "news."}, nil),
+		testRule("release", "", []string{"release"}, models.RuleCondition{Field: "title", Op: "contains", Value: "release"}, nil),
+	})
+	subject, err := newRuleSubject("https://github.com/golang/go", "Go Release Notes", "", nil)
+	if err != nil {
+		t.Fatalf("newRuleSubject returned error: %v", err)
+	}
+
+	if candidates := set.forHost(subject.Host); len(candidates) != 2 {
+		t.Fatalf("forHost returned %d rules, want 2", len(candidates))
+	}
+
+	evaluation := set.explain(subject, nil)
+	if len(evaluation.Rules) != 3 {
+		t.Fatalf("explain returned %d rule traces, want 3", len(evaluation.Rules))
+	}
+	wantMatched := []bool{true, false, true}
+	for index, trace := range evaluation.Rules {
+		if trace.Matched != wantMatched[index] {
+			t.Errorf("rule %s matched = %v, want %v", trace.RuleName, trace.Matched, wantMatched[index])
+		}
+		if trace.Skipped {
+			t.Errorf("rule %s was skipped", trace.RuleName)
+		}
+	}
+
+	news := evaluation.Rules[1]
+	if news.RuleName != "news" {
+		t.Fatalf("second trace is %q, want news", news.RuleName)
+	}
+	if len(news.Conditions) != 1 {
+		t.Fatalf("news rule has %d condition results, want 1", len(news.Conditions))
+	}
+	condition := news.Conditions[0]
+	if condition.Field != "host" || condition.Op != "prefix" || condition.Value != "news." || condition.Matched {
+		t.Errorf("news condition = %+v, want unmatched host prefix news.", condition)
+	}
+}
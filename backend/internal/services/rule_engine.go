@@ -0,0 +1,566 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"bookmarks-backend/internal/models"
+	"bookmarks-backend/internal/utils"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const maxRuleConditionDepth = 8
+
+var (
+	ruleConditionFields = map[string]struct{}{
+		"host": {}, "url": {}, "path": {}, "title": {}, "description": {}, "tag": {},
+	}
+	ruleConditionOps = map[string]struct{}{
+		"prefix": {}, "suffix": {}, "contains": {}, "equals": {}, "regex": {},
+	}
+)
+
+type ruleMatch struct {
+	ID             string
+	Name           string
+	Priority       int
+	Enabled        bool
+	StopProcessing bool
+	Condition      compiledCondition
+	Actions        compiledActions
+	RawActions     *models.RuleActions
+	CategoryName   *string
+	Tags           []models.Tag
+}
+
+type compiledActions struct {
+	RemoveTags   []string
+	TitlePattern *regexp.Regexp
+	TitleReplace string
+	URLPattern   *regexp.Regexp
+	URLReplace   string
+	ReadLater    bool
+	Reject       bool
+	RejectReason string
+}
+
+type compiledCondition struct {
+	Group    string
+	Field    string
+	Op       string
+	Value    string
+	Negate   bool
+	Pattern  *regexp.Regexp
+	Children []compiledCondition
+}
+
+type ruleSubject struct {
+	RawURL      string
+	RawTitle    string
+	URL         string
+	Host        string
+	Path        string
+	Title       string
+	Description string
+	Tags        []string
+}
+
+type RuleConditionResult struct {
+	Group    string                `json:"group,omitempty"`
+	Field    string                `json:"field,omitempty"`
+	Op       string                `json:"op,omitempty"`
+	Value    string                `json:"value,omitempty"`
+	Negate   bool                  `json:"negate,omitempty"`
+	Matched  bool                  `json:"matched"`
+	Children []RuleConditionResult `json:"children,omitempty"`
+}
+
+type RuleTrace struct {
+	RuleID          string                `json:"ruleId"`
+	RuleName        string                `json:"ruleName"`
+	Priority        int                   `json:"priority"`
+	Enabled         bool                  `json:"enabled"`
+	Skipped         bool                  `json:"skipped"`
+	StopProcessing  bool                  `json:"stopProcessing"`
+	Matched         bool                  `json:"matched"`
+	Conditions      []RuleConditionResult `json:"conditions"`
+	Category        *string               `json:"category"`
+	Tags            []string              `json:"tags"`
+	Actions         *models.RuleActions   `json:"actions"`
+	AppliedCategory bool                  `json:"appliedCategory"`
+	Rejected        bool                  `json:"rejected"`
+}
+
+type RuleEvaluation struct {
+	URL               string      `json:"url"`
+	Title             string      `json:"title"`
+	Category          string      `json:"category"`
+	SuggestedCategory string      `json:"suggestedCategory,omitempty"`
+	Tags              []string    `json:"tags"`
+	RemoveTags        []string    `json:"removeTags"`
+	ReadLater         bool        `json:"readLater"`
+	RejectedBy        *string     `json:"rejectedBy"`
+	RejectReason      string      `json:"rejectReason,omitempty"`
+	Rules             []RuleTrace `json:"rules"`
+}
+
+type RuleRejectedError struct {
+	RuleName string
+	Reason   string
+}
+
+func (err *RuleRejectedError) Error() string {
+	if err.Reason != "" {
+		return fmt.Sprintf("bookmark rejected by rule %q: %s", err.RuleName, err.Reason)
+	}
+	return fmt.Sprintf("bookmark rejected by rule %q", err.RuleName)
+}
+
+func (evaluation *RuleEvaluation) rejection() error {
+	if evaluation.RejectedBy == nil {
+		return nil
+	}
+	return &RuleRejectedError{RuleName: *evaluation.RejectedBy, Reason: evaluation.RejectReason}
+}
+
+func newRuleSubject(normalizedURL string, title string, description string, tags []string) (ruleSubject, error) {
+	parsed, err := url.Parse(normalizedURL)
+	if err != nil {
+		return ruleSubject{}, err
+	}
+
+	return ruleSubject{
+		RawURL:      normalizedURL,
+		RawTitle:    title,
+		URL:         strings.ToLower(normalizedURL),
+		Host:        strings.ToLower(parsed.Hostname()),
+		Path:        strings.ToLower(parsed.EscapedPath()),
+		Title:       strings.ToLower(title),
+		Description: strings.ToLower(description),
+		Tags:        normalizeTags(tags),
+	}, nil
+}
+
+func (subject ruleSubject) withURL(normalizedURL string) ruleSubject {
+	parsed, err := url.Parse(normalizedURL)
+	if err != nil {
+		return subject
+	}
+	subject.RawURL = normalizedURL
+	subject.URL = strings.ToLower(normalizedURL)
+	subject.Host = strings.ToLower(parsed.Hostname())
+	subject.Path = strings.ToLower(parsed.EscapedPath())
+	return subject
+}
+
+func (subject ruleSubject) withTitle(title string) ruleSubject {
+	subject.RawTitle = title
+	subject.Title = strings.ToLower(title)
+	return subject
+}
+
+func validateRuleActions(actions *models.RuleActions) error {
+	_, err := compileActions(actions)
+	return err
+}
+
+func compileActions(actions *models.RuleActions) (compiledActions, error) {
+	if actions == nil {
+		return compiledActions{}, nil
+	}
+
+	compiled := compiledActions{
+		RemoveTags:   normalizeTags(actions.RemoveTags),
+		TitleReplace: actions.TitleReplace,
+		URLReplace:   strings.TrimSpace(actions.URLReplace),
+		ReadLater:    actions.ReadLater,
+		Reject:       actions.Reject,
+		RejectReason: strings.TrimSpace(actions.RejectReason),
+	}
+	if pattern := strings.TrimSpace(actions.TitlePattern); pattern != "" {
+		compiledPattern, err := regexp.Compile(pattern)
+		if err != nil {
+			return compiled, fmt.Errorf("invalid title pattern %q: %w", pattern, err)
+		}
+		compiled.TitlePattern = compiledPattern
+	}
+	if pattern := strings.TrimSpace(actions.URLPattern); pattern != "" {
+		if compiled.URLReplace == "" {
+			return compiled, errors.New("url rewrite requires a replacement")
+		}
+		compiledPattern, err := regexp.Compile(pattern)
+		if err != nil {
+			return compiled, fmt.Errorf("invalid url pattern %q: %w", pattern, err)
+		}
+		compiled.URLPattern = compiledPattern
+	}
+	return compiled, nil
+}
+
+func (actions compiledActions) apply(subject ruleSubject) ruleSubject {
+	if actions.URLPattern != nil {
+		rewritten := actions.URLPattern.ReplaceAllString(subject.RawURL, actions.URLReplace)
+		if normalized, err := utils.NormalizeURL(rewritten); err == nil {
+			subject = subject.withURL(normalized)
+		}
+	}
+	if actions.TitlePattern != nil {
+		if rewritten := strings.TrimSpace(actions.TitlePattern.ReplaceAllString(subject.RawTitle, actions.TitleReplace)); rewritten != "" {
+			subject = subject.withTitle(rewritten)
+		}
+	}
+	return subject
+}
+
+func validateRuleCondition(condition *models.RuleCondition) error {
+	if condition == nil {
+		return nil
+	}
+	_, err := compileCondition(*condition, 0)
+	return err
+}
+
+func compileRule(hostPrefix string, urlPrefix string, pathPrefix string, titleContains string, condition *models.RuleCondition) (compiledCondition, error) {
+	root := compiledCondition{Group: "all"}
+	legacy := []models.RuleCondition{
+		{Field: "host", Op: "prefix", Value: hostPrefix},
+		{Field: "url", Op: "prefix", Value: urlPrefix},
+		{Field: "path", Op: "prefix", Value: pathPrefix},
+		{Field: "title", Op: "contains", Value: titleContains},
+	}
+	for _, leaf := range legacy {
+		if strings.TrimSpace(leaf.Value) == "" {
+			continue
+		}
+		compiled, err := compileCondition(leaf, 1)
+		if err != nil {
+			return root, err
+		}
+		root.Children = append(root.Children, compiled)
+	}
+	if condition != nil {
+		compiled, err := compileCondition(*condition, 1)
+		if err != nil {
+			return root, err
+		}
+		root.Children = append(root.Children, compiled)
+	}
+	return root, nil
+}
+
+func compileCondition(condition models.RuleCondition, depth int) (compiledCondition, error) {
+	if depth > maxRuleConditionDepth {
+		return compiledCondition{}, fmt.Errorf("conditions are nested deeper than %d levels", maxRuleConditionDepth)
+	}
+
+	isGroup := len(condition.All) > 0 || len(condition.Any) > 0
+	if isGroup {
+		if len(condition.All) > 0 && len(condition.Any) > 0 {
+			return compiledCondition{}, errors.New("a condition group must use either all or any, not both")
+		}
+		if condition.Field != "" || condition.Op != "" || condition.Value != "" {
+			return compiledCondition{}, errors.New("a condition group cannot also set field, op or value")
+		}
+		compiled := compiledCondition{Group: "all", Negate: condition.Negate}
+		children := condition.All
+		if len(condition.Any) > 0 {
+			compiled.Group = "any"
+			children = condition.Any
+		}
+		for _, child := range children {
+			compiledChild, err := compileCondition(child, depth+1)
+			if err != nil {
+				return compiledCondition{}, err
+			}
+			compiled.Children = append(compiled.Children, compiledChild)
+		}
+		return compiled, nil
+	}
+
+	field := strings.ToLower(strings.TrimSpace(condition.Field))
+	op := strings.ToLower(strings.TrimSpace(condition.Op))
+	value := strings.TrimSpace(condition.Value)
+	if _, ok := ruleConditionFields[field]; !ok {
+		return compiledCondition{}, fmt.Errorf("unknown condition field %q", condition.Field)
+	}
+	if _, ok := ruleConditionOps[op]; !ok {
+		return compiledCondition{}, fmt.Errorf("unknown condition op %q", condition.Op)
+	}
+	if value == "" {
+		return compiledCondition{}, fmt.Errorf("condition on %s requires a value", field)
+	}
+
+	compiled := compiledCondition{Field: field, Op: op, Value: value, Negate: condition.Negate}
+	if op == "regex" {
+		pattern, err := regexp.Compile("(?i)" + value)
+		if err != nil {
+			return compiledCondition{}, fmt.Errorf("invalid regex %q: %w", value, err)
+		}
+		compiled.Pattern = pattern
+	} else {
+		compiled.Value = strings.ToLower(value)
+	}
+	return compiled, nil
+}
+
+func (condition compiledCondition) evaluate(subject ruleSubject) RuleConditionResult {
+	result := RuleConditionResult{
+		Group:  condition.Group,
+		Field:  condition.Field,
+		Op:     condition.Op,
+		Value:  condition.Value,
+		Negate: condition.Negate,
+	}
+
+	if condition.Group != "" {
+		matched := condition.Group == "all"
+		for _, child := range condition.Children {
+			childResult := child.evaluate(subject)
+			result.Children = append(result.Children, childResult)
+			if condition.Group == "all" {
+				matched = matched && childResult.Matched
+			} else {
+				matched = matched || childResult.Matched
+			}
+		}
+		result.Matched = matched != condition.Negate
+		return result
+	}
+
+	var values []string
+	switch condition.Field {
+	case "host":
+		values = []string{subject.Host}
+	case "url":
+		values = []string{subject.URL}
+	case "path":
+		values = []string{subject.Path}
+	case "title":
+		values = []string{subject.Title}
+	case "description":
+		values = []string{subject.Description}
+	case "tag":
+		values = subject.Tags
+	}
+
+	matched := false
+	for _, value := range values {
+		if condition.matchValue(value) {
+			matched = true
+			break
+		}
+	}
+	result.Matched = matched != condition.Negate
+	return result
+}
+
+func (condition compiledCondition) matchValue(value string) bool {
+	switch condition.Op {
+	case "prefix":
+		return strings.HasPrefix(value, condition.Value)
+	case "suffix":
+		return strings.HasSuffix(value, condition.Value)
+	case "contains":
+		return strings.Contains(value, condition.Value)
+	case "equals":
+		return value == condition.Value
+	case "regex":
+		return condition.Pattern.MatchString(value)
+	}
+	return false
+}
+
+func (rule ruleMatch) evaluate(subject ruleSubject) (bool, []RuleConditionResult) {
+	result := rule.Condition.evaluate(subject)
+	conditions := result.Children
+	if conditions == nil {
+		conditions = []RuleConditionResult{}
+	}
+	return result.Matched, conditions
+}
+
+func evaluateRules(rules []ruleMatch, subject ruleSubject, metadata *utils.Metadata) RuleEvaluation {
+	evaluation := RuleEvaluation{Rules: []RuleTrace{}}
+	mergedTags := []string{}
+	removeTags := []string{}
+	stopped := false
+	for _, rule := range rules {
+		trace := RuleTrace{
+			RuleID:         rule.ID,
+			RuleName:       rule.Name,
+			Priority:       rule.Priority,
+			Enabled:        rule.Enabled,
+			Skipped:        stopped || !rule.Enabled,
+			StopProcessing: rule.StopProcessing,
+			Conditions:     []RuleConditionResult{},
+			Category:       rule.CategoryName,
+			Tags:           []string{},
+			Actions:        rule.RawActions,
+		}
+		for _, tag := range rule.Tags {
+			trace.Tags = append(trace.Tags, tag.Name)
+		}
+
+		if !trace.Skipped {
+			trace.Matched, trace.Conditions = rule.evaluate(subject)
+		}
+		if trace.Matched {
+			if rule.Actions.Reject {
+				name := rule.Name
+				evaluation.RejectedBy = &name
+				evaluation.RejectReason = rule.Actions.RejectReason
+				trace.Rejected = true
+				stopped = true
+				evaluation.Rules = append(evaluation.Rules, trace)
+				continue
+			}
+			if evaluation.Category == "" && rule.CategoryName != nil {
+				evaluation.Category = *rule.CategoryName
+				trace.AppliedCategory = true
+			}
+			mergedTags = append(mergedTags, trace.Tags...)
+			removeTags = append(removeTags, rule.Actions.RemoveTags...)
+			evaluation.ReadLater = evaluation.ReadLater || rule.Actions.ReadLater
+			subject = rule.Actions.apply(subject)
+			stopped = rule.StopProcessing
+		}
+		evaluation.Rules = append(evaluation.Rules, trace)
+	}
+
+	if metadata != nil && !stopped {
+		if evaluation.Category == "" {
+			evaluation.SuggestedCategory = utils.NormalizeName(metadata.Category)
+		}
+		mergedTags = append(mergedTags, metadata.Tags...)
+	}
+
+	evaluation.URL = subject.RawURL
+	evaluation.Title = subject.RawTitle
+	evaluation.RemoveTags = normalizeTags(removeTags)
+	evaluation.Tags = removeTagNames(normalizeTags(mergedTags), evaluation.RemoveTags)
+	return evaluation
+}
+
+func removeTagNames(tags []string, remove []string) []string {
+	if len(remove) == 0 {
+		return tags
+	}
+	removed := map[string]struct{}{}
+	for _, tag := range remove {
+		removed[tag] = struct{}{}
+	}
+	kept := []string{}
+	for _, tag := range tags {
+		if _, ok := removed[tag]; !ok {
+			kept = append(kept, tag)
+		}
+	}
+	return kept
+}
+
+func loadRuleMatches(ctx context.Context, pool *pgxpool.Pool) ([]ruleMatch, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT r.id, r.name, r.priority, r.enabled, r.stop_processing,
+		r.host_prefix, r.url_prefix, r.path_prefix, r.title_contains, r.conditions, r.actions, c.name
+		FROM rules r
+		LEFT JOIN categories c ON c.id = r.category_id
+		ORDER BY r.priority ASC, r.created_at ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []ruleMatch{}
+	for rows.Next() {
+		var rule ruleMatch
+		var hostPrefix, urlPrefix, pathPrefix, titleContains string
+		var conditions *models.RuleCondition
+		if err := rows.Scan(&rule.ID, &rule.Name, &rule.Priority, &rule.Enabled, &rule.StopProcessing, &hostPrefix, &urlPrefix, &pathPrefix, &titleContains, &conditions, &rule.RawActions, &rule.CategoryName); err != nil {
+			return nil, err
+		}
+		compiled, err := compileRule(hostPrefix, urlPrefix, pathPrefix, titleContains, conditions)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+		rule.Condition = compiled
+		actions, err := compileActions(rule.RawActions)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+		rule.Actions = actions
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	tagsByRule, err := fetchAllRuleTags(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+	for index := range rules {
+		rules[index].Tags = tagsByRule[rules[index].ID]
+		if rules[index].Tags == nil {
+			rules[index].Tags = []models.Tag{}
+		}
+	}
+
+	return rules, nil
+}
+
+func fetchAllRuleTags(ctx context.Context, pool *pgxpool.Pool) (map[string][]models.Tag, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT rt.rule_id, t.id, t.name
+		FROM rule_tags rt
+		INNER JOIN tags t ON t.id = rt.tag_id
+		ORDER BY t.name ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tagsByRule := map[string][]models.Tag{}
+	for rows.Next() {
+		var ruleID string
+		var tag models.Tag
+		if err := rows.Scan(&ruleID, &tag.ID, &tag.Name); err != nil {
+			return nil, err
+		}
+		tagsByRule[ruleID] = append(tagsByRule[ruleID], tag)
+	}
+	return tagsByRule, rows.Err()
+}
+
+func fetchRuleTags(ctx context.Context, pool *pgxpool.Pool, ruleID string) ([]models.Tag, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT t.id, t.name
+		FROM tags t
+		INNER JOIN rule_tags rt ON rt.tag_id = t.id
+		WHERE rt.rule_id = $1
+		ORDER BY t.name ASC
+	`, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
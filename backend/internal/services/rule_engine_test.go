@@ -0,0 +1,308 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"bookmarks-backend/internal/models"
+	"bookmarks-backend/internal/utils"
+)
+
+func testRule(name string, category string, tags []string, condition models.RuleCondition, actions *models.RuleActions) ruleMatch {
+	compiled, err := compileRule("", "", "", "", &condition)
+	if err != nil {
+		panic(err)
+	}
+	compiledActions, err := compileActions(actions)
+	if err != nil {
+		panic(err)
+	}
+	rule := ruleMatch{
+		ID:         name,
+		Name:       name,
+		Enabled:    true,
+		Condition:  compiled,
+		Actions:    compiledActions,
+		RawActions: actions,
+		Tags:       []models.Tag{},
+	}
+	if category != "" {
+		rule.CategoryName = &category
+	}
+	for _, tag := range tags {
+		rule.Tags = append(rule.Tags, models.Tag{ID: tag, Name: tag})
+	}
+	return rule
+}
+
+func disabledRule(rule ruleMatch) ruleMatch {
+	rule.Enabled = false
+	return rule
+}
+
+func stoppingRule(rule ruleMatch) ruleMatch {
+	rule.StopProcessing = true
+	return rule
+}
+
+func TestEvaluateRules(t *testing.T) {
+	onGitHub := models.RuleCondition{Field: "host", Op: "equals", Value: "github.com"}
+	onExample := models.RuleCondition{Field: "host", Op: "equals", Value: "example.com"}
+	titleHasRelease := models.RuleCondition{Field: "title", Op: "contains", Value: "release"}
+
+	tests := []struct {
+		name          string
+		rules         []ruleMatch
+		metadata      *utils.Metadata
+		wantURL       string
+		wantTitle     string
+		wantCategory  string
+		wantSuggested string
+		wantTags      []string
+		wantReadLater bool
+		wantRejected  string
+		wantMatched   []bool
+		wantSkipped   []bool
+	}{
+		{
+			name: "first matching category wins and tags merge in order",
+			rules: []ruleMatch{
+				testRule("code", "Code", []string{"dev"}, onGitHub, nil),
+				testRule("other", "Other", nil, onExample, nil),
+				testRule("go", "Go", []string{"go", "dev"}, models.RuleCondition{Field: "path", Op: "prefix", Value: "/golang"}, &models.RuleActions{ReadLater: true}),
+			},
+			wantURL:       "https://github.com/golang/go",
+			wantTitle:     "Go Release Notes",
+			wantCategory:  "Code",
+			wantTags:      []string{"dev", "go"},
+			wantReadLater: true,
+			wantMatched:   []bool{true, false, true},
+			wantSkipped:   []bool{false, false, false},
+		},
+		{
+			name: "disabled rules are skipped",
+			rules: []ruleMatch{
+				disabledRule(testRule("code", "Code", []string{"dev"}, onGitHub, nil)),
+				testRule("release", "Releases", nil, titleHasRelease, nil),
+			},
+			wantURL:      "https://github.com/golang/go",
+			wantTitle:    "Go Release Notes",
+			wantCategory: "Releases",
+			wantTags:     []string{},
+			wantMatched:  []bool{false, true},
+			wantSkipped:  []bool{true, false},
+		},
+		{
+			name: "later rules see earlier rewrites",
+			rules: []ruleMatch{
+				testRule("rename", "", nil, onGitHub, &models.RuleActions{TitlePattern: "Notes$", TitleReplace: "Changelog", URLPattern: "github\\.com/golang/go", URLReplace: "go.dev/doc"}),
+				testRule("changelog", "Changelog", nil, models.RuleCondition{Field: "title", Op: "suffix", Value: "changelog"}, nil),
+				testRule("docs", "", []string{"docs"}, models.RuleCondition{Field: "host", Op: "equals", Value: "go.dev"}, nil),
+			},
+			wantURL:      "https://go.dev/doc",
+			wantTitle:    "Go Release Changelog",
+			wantCategory: "Changelog",
+			wantTags:     []string{"docs"},
+			wantMatched:  []bool{true, true, true},
+			wantSkipped:  []bool{false, false, false},
+		},
+		{
+			name: "stop processing skips later rules and metadata",
+			rules: []ruleMatch{
+				stoppingRule(testRule("code", "", []string{"dev"}, onGitHub, nil)),
+				testRule("release", "Releases", []string{"release"}, titleHasRelease, nil),
+			},
+			metadata:    &utils.Metadata{Category: "Software", Tags: []string{"golang"}},
+			wantURL:     "https://github.com/golang/go",
+			wantTitle:   "Go Release Notes",
+			wantTags:    []string{"dev"},
+			wantMatched: []bool{true, false},
+			wantSkipped: []bool{false, true},
+		},
+		{
+			name: "stop processing on a rule that does not match has no effect",
+			rules: []ruleMatch{
+				stoppingRule(testRule("other", "Other", nil, onExample, nil)),
+				testRule("release", "Releases", nil, titleHasRelease, nil),
+			},
+			wantURL:      "https://github.com/golang/go",
+			wantTitle:    "Go Release Notes",
+			wantCategory: "Releases",
+			wantTags:     []string{},
+			wantMatched:  []bool{false, true},
+			wantSkipped:  []bool{false, false},
+		},
+		{
+			name: "reject stops evaluation and discards earlier actions",
+			rules: []ruleMatch{
+				testRule("code", "Code", []string{"dev"}, onGitHub, nil),
+				testRule("block", "", nil, titleHasRelease, &models.RuleActions{Reject: true, RejectReason: "no release notes"}),
+				testRule("release", "Releases", nil, titleHasRelease, nil),
+			},
+			metadata:     &utils.Metadata{Tags: []string{"golang"}},
+			wantURL:      "https://github.com/golang/go",
+			wantTitle:    "Go Release Notes",
+			wantCategory: "Code",
+			wantTags:     []string{"dev"},
+			wantRejected: "block",
+			wantMatched:  []bool{true, true, false},
+			wantSkipped:  []bool{false, false, true},
+		},
+		{
+			name: "metadata category is only a suggestion",
+			rules: []ruleMatch{
+				testRule("code", "", []string{"dev"}, onGitHub, &models.RuleActions{RemoveTags: []string{"Spam"}}),
+			},
+			metadata:      &utils.Metadata{Category: "  Software ", Tags: []string{"Golang", "spam", "dev"}},
+			wantURL:       "https://github.com/golang/go",
+			wantTitle:     "Go Release Notes",
+			wantSuggested: "software",
+			wantTags:      []string{"dev", "golang"},
+			wantMatched:   []bool{true},
+			wantSkipped:   []bool{false},
+		},
+		{
+			name: "rule category suppresses the metadata suggestion",
+			rules: []ruleMatch{
+				testRule("code", "Code", nil, onGitHub, nil),
+			},
+			metadata:     &utils.Metadata{Category: "Software"},
+			wantURL:      "https://github.com/golang/go",
+			wantTitle:    "Go Release Notes",
+			wantCategory: "Code",
+			wantTags:     []string{},
+			wantMatched:  []bool{true},
+			wantSkipped:  []bool{false},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			subject, err := newRuleSubject("https://github.com/golang/go", "Go Release Notes", "", nil)
+			if err != nil {
+				t.Fatalf("newRuleSubject returned error: %v", err)
+			}
+			evaluation := evaluateRules(test.rules, subject, test.metadata)
+
+			if evaluation.URL != test.wantURL {
+				t.Errorf("URL = %q, want %q", evaluation.URL, test.wantURL)
+			}
+			if evaluation.Title != test.wantTitle {
+				t.Errorf("Title = %q, want %q", evaluation.Title, test.wantTitle)
+			}
+			if evaluation.Category != test.wantCategory {
+				t.Errorf("Category = %q, want %q", evaluation.Category, test.wantCategory)
+			}
+			if evaluation.SuggestedCategory != test.wantSuggested {
+				t.Errorf("SuggestedCategory = %q, want %q", evaluation.SuggestedCategory, test.wantSuggested)
+			}
+			if !slices.Equal(evaluation.Tags, test.wantTags) {
+				t.Errorf("Tags = %v, want %v", evaluation.Tags, test.wantTags)
+			}
+			if evaluation.ReadLater != test.wantReadLater {
+				t.Errorf("ReadLater = %v, want %v", evaluation.ReadLater, test.wantReadLater)
+			}
+
+			rejectedBy := ""
+			if evaluation.RejectedBy != nil {
+				rejectedBy = *evaluation.RejectedBy
+			}
+			if rejectedBy != test.wantRejected {
+				t.Errorf("RejectedBy = %q, want %q", rejectedBy, test.wantRejected)
+			}
+			var rejected *RuleRejectedError
+			if errors.As(evaluation.rejection(), &rejected) != (test.wantRejected != "") {
+				t.Errorf("rejection() = %v, want rejection by %q", evaluation.rejection(), test.wantRejected)
+			}
+
+			if len(evaluation.Rules) != len(test.rules) {
+				t.Fatalf("got %d rule traces, want %d", len(evaluation.Rules), len(test.rules))
+			}
+			for index, trace := range evaluation.Rules {
+				if trace.Matched != test.wantMatched[index] {
+					t.Errorf("rule %s matched = %v, want %v", trace.RuleName, trace.Matched, test.wantMatched[index])
+				}
+				if trace.Skipped != test.wantSkipped[index] {
+					t.Errorf("rule %s skipped = %v, want %v", trace.RuleName, trace.Skipped, test.wantSkipped[index])
+				}
+			}
+		})
+	}
+}
+
+func TestCompileCondition(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition models.RuleCondition
+		title     string
+		wantMatch bool
+		wantErr   bool
+	}{
+		{name: "contains ignores case", condition: models.RuleCondition{Field: "title", Op: "contains", Value: "GO"}, title: "Learning Go", wantMatch: true},
+		{name: "regex ignores case", condition: models.RuleCondition{Field: "title", Op: "regex", Value: "^learning\\s+go$"}, title: "Learning Go", wantMatch: true},
+		{name: "negated leaf", condition: models.RuleCondition{Field: "title", Op: "contains", Value: "rust", Negate: true}, title: "Learning Go", wantMatch: true},
+		{
+			name: "any group",
+			condition: models.RuleCondition{Any: []models.RuleCondition{
+				{Field: "title", Op: "prefix", Value: "rust"},
+				{Field: "tag", Op: "equals", Value: "go"},
+			}},
+			title:     "Learning Go",
+			wantMatch: true,
+		},
+		{
+			name: "negated all group",
+			condition: models.RuleCondition{Negate: true, All: []models.RuleCondition{
+				{Field: "host", Op: "equals", Value: "example.com"},
+				{Field: "tag", Op: "equals", Value: "go"},
+			}},
+			title:     "Learning Go",
+			wantMatch: false,
+		},
+		{name: "unknown field", condition: models.RuleCondition{Field: "body", Op: "contains", Value: "go"}, wantErr: true},
+		{name: "unknown op", condition: models.RuleCondition{Field: "title", Op: "like", Value: "go"}, wantErr: true},
+		{name: "missing value", condition: models.RuleCondition{Field: "title", Op: "contains", Value: " "}, wantErr: true},
+		{name: "invalid regex", condition: models.RuleCondition{Field: "title", Op: "regex", Value: "("}, wantErr: true},
+		{
+			name: "group with both all and any",
+			condition: models.RuleCondition{
+				All: []models.RuleCondition{{Field: "title", Op: "contains", Value: "go"}},
+				Any: []models.RuleCondition{{Field: "title", Op: "contains", Value: "go"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			compiled, err := compileCondition(test.condition, 0)
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("compileCondition succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("compileCondition returned error: %v", err)
+			}
+			subject, err := newRuleSubject("https://example.com/learn", test.title, "", []string{"Go"})
+			if err != nil {
+				t.Fatalf("newRuleSubject returned error: %v", err)
+			}
+			if got := compiled.evaluate(subject).Matched; got != test.wantMatch {
+				t.Errorf("matched = %v, want %v", got, test.wantMatch)
+			}
+		})
+	}
+}
+
+func TestCompileConditionDepthLimit(t *testing.T) {
+	condition := models.RuleCondition{Field: "title", Op: "contains", Value: "go"}
+	for depth := 0; depth <= maxRuleConditionDepth; depth++ {
+		condition = models.RuleCondition{All: []models.RuleCondition{condition}}
+	}
+	if _, err := compileCondition(condition, 0); err == nil {
+		t.Fatal("compileCondition accepted a condition nested too deep")
+	}
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 
 	"bookmarks-backend/internal/models"
@@ -13,26 +14,44 @@ import (
 )
 
 type RuleService struct {
-	Pool *pgxpool.Pool
+	Pool      *pgxpool.Pool
+	RuleCache *RuleCache
+	Metadata  *MetadataService
 }
 
 type RuleInput struct {
-	Name          string
-	HostPrefix    string
-	URLPrefix     string
-	PathPrefix    string
-	TitleContains string
-	Category      string
-	Tags          []string
+	Name           string
+	HostPrefix     string
+	URLPrefix      string
+	PathPrefix     string
+	TitleContains  string
+	Conditions     *models.RuleCondition
+	Priority       *int
+	Enabled        *bool
+	StopProcessing *bool
+	Actions        *models.RuleActions
+	Category       string
+	Tags           []string
+}
+
+const ruleSelectSQL = `
+	SELECT r.id, r.name, r.host_prefix, r.url_prefix, r.path_prefix, r.title_contains,
+	r.conditions, r.priority, r.enabled, r.stop_processing, r.actions, r.category_id, c.name,
+	r.match_count, r.last_matched_at, (SELECT COUNT(*) FROM rule_matches rm WHERE rm.rule_id = r.id),
+	r.review_note, r.created_at, r.updated_at
+	FROM rules r
+	LEFT JOIN categories c ON c.id = r.category_id
+`
+
+func scanRule(row pgx.Row, rule *models.Rule) error {
+	return row.Scan(&rule.ID, &rule.Name, &rule.HostPrefix, &rule.URLPrefix, &rule.PathPrefix, &rule.TitleContains,
+		&rule.Conditions, &rule.Priority, &rule.Enabled, &rule.StopProcessing, &rule.Actions, &rule.CategoryID, &rule.CategoryName,
+		&rule.Stats.MatchCount, &rule.Stats.LastMatchedAt, &rule.Stats.AffectedBookmarks, &rule.ReviewNote, &rule.CreatedAt, &rule.UpdatedAt)
 }
 
 func (service *RuleService) List(ctx context.Context) ([]models.Rule, error) {
-	rows, err := service.Pool.Query(ctx, `
-		SELECT r.id, r.name, r.host_prefix, r.url_prefix, r.path_prefix, r.title_contains,
-		r.category_id, c.name, r.created_at, r.updated_at
-		FROM rules r
-		LEFT JOIN categories c ON c.id = r.category_id
-		ORDER BY r.created_at ASC
+	rows, err := service.Pool.Query(ctx, ruleSelectSQL+`
+		ORDER BY r.priority ASC, r.created_at ASC
 	`)
 	if err != nil {
 		return nil, err
@@ -42,33 +61,52 @@ func (service *RuleService) List(ctx context.Context) ([]models.Rule, error) {
 	rules := []models.Rule{}
 	for rows.Next() {
 		var rule models.Rule
-		if err := rows.Scan(&rule.ID, &rule.Name, &rule.HostPrefix, &rule.URLPrefix, &rule.PathPrefix, &rule.TitleContains, &rule.CategoryID, &rule.CategoryName, &rule.CreatedAt, &rule.UpdatedAt); err != nil {
+		if err := scanRule(rows, &rule); err != nil {
 			return nil, err
 		}
 		rules = append(rules, rule)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	tagsByRule, err := fetchAllRuleTags(ctx, service.Pool)
+	if err != nil {
+		return nil, err
+	}
 	for index := range rules {
-		tags, err := service.fetchRuleTags(ctx, rules[index].ID)
-		if err != nil {
-			return nil, err
+		rules[index].Tags = tagsByRule[rules[index].ID]
+		if rules[index].Tags == nil {
+			rules[index].Tags = []models.Tag{}
 		}
-		rules[index].Tags = tags
 	}
 
 	return rules, nil
 }
 
-func (service *RuleService) Create(ctx context.Context, input RuleInput) (*models.Rule, error) {
-	if strings.TrimSpace(input.Name) == "" {
-		return nil, errors.New("name is required")
+func (service *RuleService) Get(ctx context.Context, id string) (*models.Rule, error) {
+	var rule models.Rule
+	if err := scanRule(service.Pool.QueryRow(ctx, ruleSelectSQL+`
+		WHERE r.id = $1
+	`, id), &rule); err != nil {
+		return nil, err
 	}
-	if !hasAnyRuleCondition(input) {
-		return nil, errors.New("at least one matching rule is required")
+
+	tags, err := fetchRuleTags(ctx, service.Pool, rule.ID)
+	if err != nil {
+		return nil, err
 	}
+	rule.Tags = tags
 
-	categoryName := utils.NormalizeName(input.Category)
-	cleanTags := normalizeTags(input.Tags)
+	return &rule, nil
+}
+
+func (service *RuleService) Create(ctx context.Context, input RuleInput) (*models.Rule, error) {
+	if err := validateRuleInput(input); err != nil {
+		return nil, err
+	}
 
 	tx, err := service.Pool.Begin(ctx)
 	if err != nil {
@@ -76,145 +114,280 @@ func (service *RuleService) Create(ctx context.Context, input RuleInput) (*model
 	}
 	defer tx.Rollback(ctx)
 
-	var categoryID *string
-	var categoryNamePtr *string
-	if categoryName != "" {
-		id, err := upsertCategory(ctx, tx, categoryName)
-		if err != nil {
-			return nil, err
-		}
-		categoryID = &id
-		categoryNamePtr = &categoryName
+	ruleID, err := insertRule(ctx, tx, input)
+	if err != nil {
+		return nil, err
 	}
 
-	var rule models.Rule
-	if err := tx.QueryRow(ctx, `
-		INSERT INTO rules (name, host_prefix, url_prefix, path_prefix, title_contains, category_id)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, name, host_prefix, url_prefix, path_prefix, title_contains, category_id, created_at, updated_at
-	`, strings.TrimSpace(input.Name), strings.TrimSpace(input.HostPrefix), strings.TrimSpace(input.URLPrefix), strings.TrimSpace(input.PathPrefix), strings.TrimSpace(input.TitleContains), categoryID).
-		Scan(&rule.ID, &rule.Name, &rule.HostPrefix, &rule.URLPrefix, &rule.PathPrefix, &rule.TitleContains, &rule.CategoryID, &rule.CreatedAt, &rule.UpdatedAt); err != nil {
+	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
+	service.RuleCache.Invalidate()
 
-	tags, err := upsertTags(ctx, tx, cleanTags)
-	if err != nil {
+	return service.Get(ctx, ruleID)
+}
+
+func (service *RuleService) Update(ctx context.Context, id string, input RuleInput) (*models.Rule, error) {
+	if err := validateRuleInput(input); err != nil {
 		return nil, err
 	}
-	if err := attachRuleTags(ctx, tx, rule.ID, tags); err != nil {
+
+	tx, err := service.Pool.Begin(ctx)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback(ctx)
 
-	rule.CategoryName = categoryNamePtr
-	rule.Tags = tags
+	if err := updateRule(ctx, tx, id, input); err != nil {
+		return nil, err
+	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
+	service.RuleCache.Invalidate()
 
-	return &rule, nil
+	return service.Get(ctx, id)
 }
 
-func (service *RuleService) Update(ctx context.Context, id string, input RuleInput) (*models.Rule, error) {
+func validateRuleInput(input RuleInput) error {
 	if strings.TrimSpace(input.Name) == "" {
-		return nil, errors.New("name is required")
+		return errors.New("name is required")
 	}
 	if !hasAnyRuleCondition(input) {
-		return nil, errors.New("at least one matching rule is required")
+		return errors.New("at least one matching rule is required")
 	}
+	if err := validateRuleCondition(input.Conditions); err != nil {
+		return err
+	}
+	return validateRuleActions(input.Actions)
+}
 
-	categoryName := utils.NormalizeName(input.Category)
-	cleanTags := normalizeTags(input.Tags)
-
-	tx, err := service.Pool.Begin(ctx)
+func upsertRuleCategory(ctx context.Context, tx pgx.Tx, category string) (*string, error) {
+	categoryName := utils.NormalizeName(category)
+	if categoryName == "" {
+		return nil, nil
+	}
+	id, err := upsertCategory(ctx, tx, categoryName, category)
 	if err != nil {
 		return nil, err
 	}
-	defer tx.Rollback(ctx)
+	return &id, nil
+}
 
-	var categoryID *string
-	var categoryNamePtr *string
-	if categoryName != "" {
-		id, err := upsertCategory(ctx, tx, categoryName)
-		if err != nil {
-			return nil, err
-		}
-		categoryID = &id
-		categoryNamePtr = &categoryName
+func insertRule(ctx context.Context, tx pgx.Tx, input RuleInput) (string, error) {
+	categoryID, err := upsertRuleCategory(ctx, tx, input.Category)
+	if err != nil {
+		return "", err
 	}
 
-	var rule models.Rule
+	enabled := true
+	if input.Enabled != nil {
+		enabled = *input.Enabled
+	}
+	stopProcessing := false
+	if input.StopProcessing != nil {
+		stopProcessing = *input.StopProcessing
+	}
+
+	var ruleID string
 	if err := tx.QueryRow(ctx, `
+		INSERT INTO rules (name, host_prefix, url_prefix, path_prefix, title_contains, conditions,
+			priority, enabled, stop_processing, actions, category_id)
+		VALUES ($1, $2, $3, $4, $5, $6,
+			COALESCE($7, (SELECT COALESCE(MAX(priority), 0) + 1 FROM rules)), $8, $9, $10, $11)
+		RETURNING id
+	`, strings.TrimSpace(input.Name), strings.TrimSpace(input.HostPrefix), strings.TrimSpace(input.URLPrefix), strings.TrimSpace(input.PathPrefix), strings.TrimSpace(input.TitleContains), input.Conditions,
+		input.Priority, enabled, stopProcessing, input.Actions, categoryID).Scan(&ruleID); err != nil {
+		return "", err
+	}
+
+	tags, err := upsertTags(ctx, tx, normalizeTags(input.Tags), input.Tags)
+	if err != nil {
+		return "", err
+	}
+	if err := attachRuleTags(ctx, tx, ruleID, tags); err != nil {
+		return "", err
+	}
+	return ruleID, nil
+}
+
+func updateRule(ctx context.Context, tx pgx.Tx, id string, input RuleInput) error {
+	categoryID, err := upsertRuleCategory(ctx, tx, input.Category)
+	if err != nil {
+		return err
+	}
+
+	commandTag, err := tx.Exec(ctx, `
 		UPDATE rules
 		SET name = $1,
 			host_prefix = $2,
 			url_prefix = $3,
 			path_prefix = $4,
 			title_contains = $5,
-			category_id = $6,
+			conditions = $6,
+			category_id = $7,
+			priority = COALESCE($8, priority),
+			enabled = COALESCE($9, enabled),
+			stop_processing = COALESCE($10, stop_processing),
+			actions = $11,
+			review_note = '',
 			updated_at = NOW()
-		WHERE id = $7
-		RETURNING id, name, host_prefix, url_prefix, path_prefix, title_contains, category_id, created_at, updated_at
-	`, strings.TrimSpace(input.Name), strings.TrimSpace(input.HostPrefix), strings.TrimSpace(input.URLPrefix), strings.TrimSpace(input.PathPrefix), strings.TrimSpace(input.TitleContains), categoryID, id).
-		Scan(&rule.ID, &rule.Name, &rule.HostPrefix, &rule.URLPrefix, &rule.PathPrefix, &rule.TitleContains, &rule.CategoryID, &rule.CreatedAt, &rule.UpdatedAt); err != nil {
-		return nil, err
+		WHERE id = $12
+	`, strings.TrimSpace(input.Name), strings.TrimSpace(input.HostPrefix), strings.TrimSpace(input.URLPrefix), strings.TrimSpace(input.PathPrefix), strings.TrimSpace(input.TitleContains), input.Conditions, categoryID,
+		input.Priority, input.Enabled, input.StopProcessing, input.Actions, id)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("rule not found")
 	}
 
 	if _, err := tx.Exec(ctx, "DELETE FROM rule_tags WHERE rule_id = $1", id); err != nil {
+		return err
+	}
+	tags, err := upsertTags(ctx, tx, normalizeTags(input.Tags), input.Tags)
+	if err != nil {
+		return err
+	}
+	return attachRuleTags(ctx, tx, id, tags)
+}
+
+func (service *RuleService) Reorder(ctx context.Context, ids []string) ([]models.Rule, error) {
+	if len(ids) == 0 {
+		return nil, errors.New("ids are required")
+	}
+
+	tx, err := service.Pool.Begin(ctx)
+	if err != nil {
 		return nil, err
 	}
-	tags, err := upsertTags(ctx, tx, cleanTags)
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, "SELECT id FROM rules ORDER BY priority ASC, created_at ASC")
 	if err != nil {
 		return nil, err
 	}
-	if err := attachRuleTags(ctx, tx, id, tags); err != nil {
+	current := []string{}
+	known := map[string]struct{}{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		current = append(current, id)
+		known[id] = struct{}{}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	rule.CategoryName = categoryNamePtr
-	rule.Tags = tags
+	ordered := []string{}
+	listed := map[string]struct{}{}
+	for _, id := range ids {
+		if _, ok := known[id]; !ok {
+			return nil, fmt.Errorf("rule %s not found", id)
+		}
+		if _, duplicate := listed[id]; duplicate {
+			continue
+		}
+		listed[id] = struct{}{}
+		ordered = append(ordered, id)
+	}
+	for _, id := range current {
+		if _, ok := listed[id]; !ok {
+			ordered = append(ordered, id)
+		}
+	}
+
+	for index, id := range ordered {
+		if _, err := tx.Exec(ctx, "UPDATE rules SET priority = $1, updated_at = NOW() WHERE id = $2", index+1, id); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
+	service.RuleCache.Invalidate()
 
-	return &rule, nil
+	return service.List(ctx)
 }
 
-func (service *RuleService) Delete(ctx context.Context, id string) error {
-	commandTag, err := service.Pool.Exec(ctx, "DELETE FROM rules WHERE id = $1", id)
+type RuleTestInput struct {
+	URL           string
+	Title         string
+	Description   string
+	Tags          []string
+	FetchMetadata bool
+}
+
+func (service *RuleService) Test(ctx context.Context, input RuleTestInput) (string, *RuleEvaluation, error) {
+	normalizedURL, err := utils.NormalizeURL(input.URL)
 	if err != nil {
-		return err
+		return "", nil, err
 	}
-	if commandTag.RowsAffected() == 0 {
-		return errors.New("rule not found")
+
+	metadata, err := service.testMetadata(ctx, normalizedURL, input.FetchMetadata)
+	if err != nil {
+		return "", nil, err
+	}
+	title := strings.TrimSpace(input.Title)
+	description := strings.TrimSpace(input.Description)
+	if metadata != nil {
+		if title == "" {
+			title = strings.TrimSpace(metadata.Title)
+		}
+		if description == "" {
+			description = strings.TrimSpace(metadata.Description)
+		}
 	}
-	return nil
-}
 
-func (service *RuleService) fetchRuleTags(ctx context.Context, ruleID string) ([]models.Tag, error) {
-	rows, err := service.Pool.Query(ctx, `
-		SELECT t.id, t.name
-		FROM tags t
-		INNER JOIN rule_tags rt ON rt.tag_id = t.id
-		WHERE rt.rule_id = $1
-		ORDER BY t.name ASC
-	`, ruleID)
+	tags, err := resolveTagAliases(ctx, service.Pool, normalizeTags(input.Tags))
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
-	defer rows.Close()
 
-	tags := []models.Tag{}
-	for rows.Next() {
-		var tag models.Tag
-		if err := rows.Scan(&tag.ID, &tag.Name); err != nil {
-			return nil, err
+	set, err := service.RuleCache.Rules(ctx, service.Pool)
+	if err != nil {
+		return "", nil, err
+	}
+
+	subject, err := newRuleSubject(normalizedURL, title, description, tags)
+	if err != nil {
+		return "", nil, err
+	}
+
+	evaluation := set.explain(subject, metadata)
+	return normalizedURL, &evaluation, nil
+}
+
+func (service *RuleService) testMetadata(ctx context.Context, normalizedURL string, fetch bool) (*utils.Metadata, error) {
+	if service.Metadata == nil {
+		return nil, nil
+	}
+	if fetch {
+		metadata, err := service.Metadata.Fetch(ctx, normalizedURL)
+		if err != nil {
+			return nil, nil
 		}
-		tags = append(tags, tag)
+		return metadata, nil
 	}
+	return service.Metadata.Cached(ctx, normalizedURL)
+}
 
-	return tags, nil
+func (service *RuleService) Delete(ctx context.Context, id string) error {
+	commandTag, err := service.Pool.Exec(ctx, "DELETE FROM rules WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("rule not found")
+	}
+	service.RuleCache.Invalidate()
+	return nil
 }
 
 func attachRuleTags(ctx context.Context, tx pgx.Tx, ruleID string, tags []models.Tag) error {
@@ -231,8 +404,199 @@ func attachRuleTags(ctx context.Context, tx pgx.Tx, ruleID string, tags []models
 }
 
 func hasAnyRuleCondition(input RuleInput) bool {
-	return strings.TrimSpace(input.HostPrefix) != "" ||
+	return input.Conditions != nil ||
+		strings.TrimSpace(input.HostPrefix) != "" ||
 		strings.TrimSpace(input.URLPrefix) != "" ||
 		strings.TrimSpace(input.PathPrefix) != "" ||
 		strings.TrimSpace(input.TitleContains) != ""
 }
+
+const (
+	RuleApplyModeTags     = "tags"
+	RuleApplyModeCategory = "tags_and_category"
+)
+
+type RuleApplyOptions struct {
+	Mode    string
+	Preview bool
+}
+
+type RuleApplyChange struct {
+	BookmarkID  string   `json:"bookmarkId"`
+	URL         string   `json:"url"`
+	Title       string   `json:"title"`
+	AddTags     []string `json:"addTags"`
+	SetCategory *string  `json:"setCategory"`
+	RuleIDs     []string `json:"ruleIds"`
+}
+
+type RuleApplyReport struct {
+	Preview bool              `json:"preview"`
+	Mode    string            `json:"mode"`
+	Scanned int               `json:"scanned"`
+	Changed int               `json:"changed"`
+	Changes []RuleApplyChange `json:"changes"`
+}
+
+type ruleTarget struct {
+	ID            string
+	URL           string
+	NormalizedURL string
+	Title         string
+	Description   string
+	CategoryName  *string
+	Tags          []string
+}
+
+func (service *RuleService) Apply(ctx context.Context, ruleID string, options RuleApplyOptions) (*RuleApplyReport, error) {
+	if options.Mode == "" {
+		options.Mode = RuleApplyModeTags
+	}
+	if options.Mode != RuleApplyModeTags && options.Mode != RuleApplyModeCategory {
+		return nil, errors.New("mode must be tags or tags_and_category")
+	}
+
+	set, err := service.RuleCache.Rules(ctx, service.Pool)
+	if err != nil {
+		return nil, err
+	}
+	rules := set.rules
+	if ruleID != "" {
+		selected := []ruleMatch{}
+		for _, rule := range rules {
+			if rule.ID == ruleID {
+				selected = append(selected, rule)
+			}
+		}
+		if len(selected) == 0 {
+			return nil, errors.New("rule not found")
+		}
+		selected[0].Enabled = true
+		selected[0].StopProcessing = false
+		rules = selected
+	}
+
+	targets, err := loadRuleTargets(ctx, service.Pool, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RuleApplyReport{Preview: options.Preview, Mode: options.Mode, Changes: []RuleApplyChange{}}
+	for _, target := range targets {
+		report.Scanned++
+		if change, ok := ruleChangeFor(rules, target, options.Mode); ok {
+			report.Changes = append(report.Changes, change)
+		}
+	}
+	report.Changed = len(report.Changes)
+
+	if options.Preview || len(report.Changes) == 0 {
+		return report, nil
+	}
+
+	tx, err := service.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, change := range report.Changes {
+		before, err := loadSnapshot(ctx, tx, change.BookmarkID)
+		if err != nil {
+			return nil, err
+		}
+		if err := applyRuleChange(ctx, tx, change, RuleMatchSourceApply); err != nil {
+			return nil, err
+		}
+		if err := recordVersion(ctx, tx, change.BookmarkID, VersionSourceRule, before); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func ruleChangeFor(rules []ruleMatch, target ruleTarget, mode string) (RuleApplyChange, bool) {
+	subject, err := newRuleSubject(target.NormalizedURL, target.Title, target.Description, target.Tags)
+	if err != nil {
+		return RuleApplyChange{}, false
+	}
+	evaluation := evaluateRules(rules, subject, nil)
+
+	existing := map[string]struct{}{}
+	for _, tag := range target.Tags {
+		existing[tag] = struct{}{}
+	}
+	change := RuleApplyChange{BookmarkID: target.ID, URL: target.URL, Title: target.Title, AddTags: []string{}, RuleIDs: evaluation.matchedRuleIDs()}
+	for _, tag := range evaluation.Tags {
+		if _, exists := existing[tag]; !exists {
+			change.AddTags = append(change.AddTags, tag)
+		}
+	}
+	if mode == RuleApplyModeCategory && target.CategoryName == nil && evaluation.Category != "" {
+		category := evaluation.Category
+		change.SetCategory = &category
+	}
+	if len(change.AddTags) == 0 && change.SetCategory == nil {
+		return change, false
+	}
+	return change, true
+}
+
+func applyRuleChange(ctx context.Context, tx pgx.Tx, change RuleApplyChange, source string) error {
+	if change.SetCategory != nil {
+		categoryID, err := upsertCategory(ctx, tx, *change.SetCategory, "")
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, "UPDATE bookmarks SET category_id = $1 WHERE id = $2", categoryID, change.BookmarkID); err != nil {
+			return err
+		}
+	}
+	tags, err := upsertTags(ctx, tx, change.AddTags, nil)
+	if err != nil {
+		return err
+	}
+	if err := attachTags(ctx, tx, change.BookmarkID, tags); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "UPDATE bookmarks SET updated_at = NOW() WHERE id = $1", change.BookmarkID); err != nil {
+		return err
+	}
+	return recordRuleMatches(ctx, tx, change.RuleIDs, change.BookmarkID, source)
+}
+
+func loadRuleTargets(ctx context.Context, db tagAliasQuerier, ids []string) ([]ruleTarget, error) {
+	rows, err := db.Query(ctx, `
+		SELECT b.id, b.url, b.normalized_url, b.title, b.description, c.name,
+		ARRAY(
+			SELECT t.name
+			FROM bookmark_tags bt
+			INNER JOIN tags t ON t.id = bt.tag_id
+			WHERE bt.bookmark_id = b.id
+		)
+		FROM bookmarks b
+		LEFT JOIN categories c ON c.id = b.category_id
+		WHERE b.deleted_at IS NULL AND ($1::uuid[] IS NULL OR b.id = ANY($1))
+		ORDER BY b.created_at DESC
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	targets := []ruleTarget{}
+	for rows.Next() {
+		var target ruleTarget
+		if err := rows.Scan(&target.ID, &target.URL, &target.NormalizedURL, &target.Title, &target.Description, &target.CategoryName, &target.Tags); err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+
+	return targets, rows.Err()
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"bookmarks-backend/internal/models"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	RuleMatchSourceCreate = "create"
+	RuleMatchSourceApply  = "apply"
+	RuleMatchSourceBulk   = "bulk"
+)
+
+type ruleStatsExecer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+func (evaluation *RuleEvaluation) matchedRuleIDs() []string {
+	ids := []string{}
+	for _, trace := range evaluation.Rules {
+		if trace.Matched && trace.RuleID != "" {
+			ids = append(ids, trace.RuleID)
+		}
+	}
+	return ids
+}
+
+func recordRuleMatches(ctx context.Context, db ruleStatsExecer, ruleIDs []string, bookmarkID string, source string) error {
+	if len(ruleIDs) == 0 {
+		return nil
+	}
+
+	if _, err := db.Exec(ctx, `
+		UPDATE rules
+		SET match_count = match_count + 1, last_matched_at = NOW()
+		WHERE id = ANY($1)
+	`, ruleIDs); err != nil {
+		return err
+	}
+	if bookmarkID == "" {
+		return nil
+	}
+
+	_, err := db.Exec(ctx, `
+		INSERT INTO rule_matches (rule_id, bookmark_id, source, matched_at)
+		SELECT rule_id, $2, $3, NOW()
+		FROM unnest($1::uuid[]) AS rule_id
+		WHERE EXISTS (SELECT 1 FROM rules WHERE id = rule_id)
+		ON CONFLICT (rule_id, bookmark_id)
+		DO UPDATE SET source = EXCLUDED.source, matched_at = EXCLUDED.matched_at
+	`, ruleIDs, bookmarkID, source)
+	return err
+}
+
+func (service *RuleService) Matches(ctx context.Context, id string, page int, pageSize int) (*models.RuleMatchListResponse, error) {
+	page = max(page, 1)
+	pageSize = max(pageSize, 1)
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	var total int
+	var exists bool
+	if err := service.Pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM rules WHERE id = $1), (SELECT COUNT(*) FROM rule_matches WHERE rule_id = $1)
+	`, id).Scan(&exists, &total); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.New("rule not found")
+	}
+
+	rows, err := service.Pool.Query(ctx, `
+		SELECT b.id, b.url, b.title, rm.source, rm.matched_at
+		FROM rule_matches rm
+		JOIN bookmarks b ON b.id = rm.bookmark_id
+		WHERE rm.rule_id = $1
+		ORDER BY rm.matched_at DESC
+		LIMIT $2 OFFSET $3
+	`, id, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches := []models.RuleMatch{}
+	for rows.Next() {
+		var match models.RuleMatch
+		if err := rows.Scan(&match.BookmarkID, &match.URL, &match.Title, &match.Source, &match.MatchedAt); err != nil {
+			return nil, err
+		}
+		matches = append(matches, match)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &models.RuleMatchListResponse{
+		Items: matches,
+		Pagination: models.Pagination{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		},
+	}, nil
+}
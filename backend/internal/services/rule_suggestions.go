@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"bookmarks-backend/internal/models"
+)
+
+type RuleSuggestionOptions struct {
+	MinSupport    int
+	MinConfidence float64
+	Limit         int
+}
+
+type RuleSuggestionTag struct {
+	Name       string  `json:"name"`
+	Confidence float64 `json:"confidence"`
+}
+
+type RuleSuggestion struct {
+	HostPrefix         string              `json:"hostPrefix"`
+	PathPrefix         string              `json:"pathPrefix"`
+	Support            int                 `json:"support"`
+	Confidence         float64             `json:"confidence"`
+	Category           string              `json:"category"`
+	CategoryConfidence float64             `json:"categoryConfidence"`
+	Tags               []RuleSuggestionTag `json:"tags"`
+	Rule               RuleDefinition      `json:"rule"`
+}
+
+type suggestionGroup struct {
+	Host       string
+	PathPrefix string
+	Total      int
+	Categories map[string]int
+	Tags       map[string]int
+}
+
+func (service *RuleService) Suggest(ctx context.Context, options RuleSuggestionOptions) ([]RuleSuggestion, error) {
+	if options.MinSupport <= 0 {
+		options.MinSupport = 5
+	}
+	if options.MinConfidence <= 0 || options.MinConfidence > 1 {
+		options.MinConfidence = 0.8
+	}
+	if options.Limit <= 0 {
+		options.Limit = 20
+	}
+
+	groups, err := service.loadSuggestionGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	set, err := service.RuleCache.Rules(ctx, service.Pool)
+	if err != nil {
+		return nil, err
+	}
+
+	byHost := map[string]*RuleSuggestion{}
+	suggestions := []RuleSuggestion{}
+	for _, group := range groups {
+		suggestion, ok := group.suggest(options)
+		if !ok {
+			continue
+		}
+		if group.PathPrefix == "" {
+			byHost[group.Host] = suggestion
+		} else if hostSuggestion := byHost[group.Host]; hostSuggestion != nil && suggestion.coveredBy(hostSuggestion.Category, suggestionTagNames(hostSuggestion.Tags)) {
+			continue
+		}
+		if coveredByRules(set.rules, group, suggestion) {
+			continue
+		}
+		suggestions = append(suggestions, *suggestion)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Support != suggestions[j].Support {
+			return suggestions[i].Support > suggestions[j].Support
+		}
+		return suggestions[i].Confidence > suggestions[j].Confidence
+	})
+	if len(suggestions) > options.Limit {
+		suggestions = suggestions[:options.Limit]
+	}
+	return suggestions, nil
+}
+
+func (service *RuleService) loadSuggestionGroups(ctx context.Context) ([]*suggestionGroup, error) {
+	rows, err := service.Pool.Query(ctx, `
+		SELECT b.normalized_url, COALESCE(c.name, ''),
+			COALESCE(array_agg(t.name) FILTER (WHERE t.name IS NOT NULL), '{}')
+		FROM bookmarks b
+		LEFT JOIN categories c ON c.id = b.category_id
+		LEFT JOIN bookmark_tags bt ON bt.bookmark_id = b.id
+		LEFT JOIN tags t ON t.id = bt.tag_id
+		WHERE b.deleted_at IS NULL
+		GROUP BY b.id, c.name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hostGroups := []*suggestionGroup{}
+	pathGroups := []*suggestionGroup{}
+	index := map[string]*suggestionGroup{}
+	add := func(host string, pathPrefix string, category string, tags []string) {
+		key := host + pathPrefix
+		group := index[key]
+		if group == nil {
+			group = &suggestionGroup{Host: host, PathPrefix: pathPrefix, Categories: map[string]int{}, Tags: map[string]int{}}
+			index[key] = group
+			if pathPrefix == "" {
+				hostGroups = append(hostGroups, group)
+			} else {
+				pathGroups = append(pathGroups, group)
+			}
+		}
+		group.Total++
+		if category != "" {
+			group.Categories[category]++
+		}
+		for _, tag := range tags {
+			group.Tags[tag]++
+		}
+	}
+
+	for rows.Next() {
+		var normalizedURL string
+		var category string
+		var tags []string
+		if err := rows.Scan(&normalizedURL, &category, &tags); err != nil {
+			return nil, err
+		}
+		parsed, err := url.Parse(normalizedURL)
+		if err != nil || parsed.Hostname() == "" {
+			continue
+		}
+		host := strings.ToLower(parsed.Hostname())
+		add(host, "", category, tags)
+		if segment, _, _ := strings.Cut(strings.TrimPrefix(parsed.EscapedPath(), "/"), "/"); segment != "" {
+			add(host, "/"+segment, category, tags)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return append(hostGroups, pathGroups...), nil
+}
+
+func (group *suggestionGroup) suggest(options RuleSuggestionOptions) (*RuleSuggestion, bool) {
+	if group.Total < options.MinSupport {
+		return nil, false
+	}
+
+	suggestion := &RuleSuggestion{
+		HostPrefix: group.Host,
+		PathPrefix: group.PathPrefix,
+		Support:    group.Total,
+		Confidence: 1,
+		Tags:       []RuleSuggestionTag{},
+	}
+	for category, count := range group.Categories {
+		confidence := float64(count) / float64(group.Total)
+		if confidence >= options.MinConfidence && (confidence > suggestion.CategoryConfidence || (confidence == suggestion.CategoryConfidence && category < suggestion.Category)) {
+			suggestion.Category = category
+			suggestion.CategoryConfidence = confidence
+		}
+	}
+	for tag, count := range group.Tags {
+		if confidence := float64(count) / float64(group.Total); confidence >= options.MinConfidence {
+			suggestion.Tags = append(suggestion.Tags, RuleSuggestionTag{Name: tag, Confidence: confidence})
+		}
+	}
+	if suggestion.Category == "" && len(suggestion.Tags) == 0 {
+		return nil, false
+	}
+	sort.Slice(suggestion.Tags, func(i, j int) bool {
+		if suggestion.Tags[i].Confidence != suggestion.Tags[j].Confidence {
+			return suggestion.Tags[i].Confidence > suggestion.Tags[j].Confidence
+		}
+		return suggestion.Tags[i].Name < suggestion.Tags[j].Name
+	})
+
+	if suggestion.Category != "" {
+		suggestion.Confidence = suggestion.CategoryConfidence
+	}
+	for _, tag := range suggestion.Tags {
+		suggestion.Confidence = min(suggestion.Confidence, tag.Confidence)
+	}
+
+	suggestion.Rule = RuleDefinition{
+		Name:       group.Host + group.PathPrefix,
+		HostPrefix: group.Host,
+		Conditions: pathSegmentCondition(group.PathPrefix),
+		Category:   suggestion.Category,
+		Tags:       suggestionTagNames(suggestion.Tags),
+	}
+	return suggestion, true
+}
+
+func pathSegmentCondition(pathPrefix string) *models.RuleCondition {
+	if pathPrefix == "" {
+		return nil
+	}
+	return &models.RuleCondition{Any: []models.RuleCondition{
+		{Field: "path", Op: "equals", Value: pathPrefix},
+		{Field: "path", Op: "prefix", Value: pathPrefix + "/"},
+	}}
+}
+
+func (suggestion *RuleSuggestion) coveredBy(category string, tags []string) bool {
+	if suggestion.Category != "" && suggestion.Category != category {
+		return false
+	}
+	for _, tag := range suggestion.Tags {
+		if !slices.Contains(tags, tag.Name) {
+			return false
+		}
+	}
+	return true
+}
+
+func coveredByRules(rules []ruleMatch, group *suggestionGroup, suggestion *RuleSuggestion) bool {
+	subject, err := newRuleSubject("https://"+group.Host+group.PathPrefix, "", "", nil)
+	if err != nil {
+		return false
+	}
+	evaluation := evaluateRules(rules, subject, nil)
+	if evaluation.Category == "" && len(evaluation.Tags) == 0 {
+		return false
+	}
+	return suggestion.coveredBy(evaluation.Category, evaluation.Tags)
+}
+
+func suggestionTagNames(tags []RuleSuggestionTag) []string {
+	names := []string{}
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
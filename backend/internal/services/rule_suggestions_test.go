@@ -0,0 +1,60 @@
+package services
+
+import "testing"
+
+func TestSuggestedPathRuleMatchesWholeSegments(t *testing.T) {
+	group := &suggestionGroup{
+		Host:       "github.com",
+		PathPrefix: "/golang",
+		Total:      5,
+		Categories: map[string]int{"go": 5},
+		Tags:       map[string]int{},
+	}
+	suggestion, ok := group.suggest(RuleSuggestionOptions{MinSupport: 5, MinConfidence: 0.8})
+	if !ok {
+		t.Fatal("group produced no suggestion")
+	}
+	if suggestion.Rule.PathPrefix != "" {
+		t.Errorf("suggested rule uses path prefix %q, want a segment condition", suggestion.Rule.PathPrefix)
+	}
+
+	condition, err := compileRule(suggestion.Rule.HostPrefix, suggestion.Rule.URLPrefix, suggestion.Rule.PathPrefix, suggestion.Rule.TitleContains, suggestion.Rule.Conditions)
+	if err != nil {
+		t.Fatalf("compileRule returned error: %v", err)
+	}
+
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{url: "https://github.com/golang", want: true},
+		{url: "https://github.com/golang/go", want: true},
+		{url: "https://github.com/Golang/tools", want: true},
+		{url: "https://github.com/golangci/golangci-lint", want: false},
+		{url: "https://github.com/rust-lang/rust", want: false},
+		{url: "https://gitlab.com/golang/go", want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			subject, err := newRuleSubject(test.url, "", "", nil)
+			if err != nil {
+				t.Fatalf("newRuleSubject returned error: %v", err)
+			}
+			if got := condition.evaluate(subject).Matched; got != test.want {
+				t.Errorf("matched = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSuggestedHostRuleHasNoPathCondition(t *testing.T) {
+	group := &suggestionGroup{Host: "github.com", Total: 5, Categories: map[string]int{"code": 5}, Tags: map[string]int{}}
+	suggestion, ok := group.suggest(RuleSuggestionOptions{MinSupport: 5, MinConfidence: 0.8})
+	if !ok {
+		t.Fatal("group produced no suggestion")
+	}
+	if suggestion.Rule.Conditions != nil || suggestion.Rule.PathPrefix != "" {
+		t.Errorf("host suggestion has path constraints: %+v", suggestion.Rule)
+	}
+}
@@ -0,0 +1,329 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"bookmarks-backend/internal/models"
+	"bookmarks-backend/internal/utils"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	RuleFormatYAML = "yaml"
+	RuleFormatJSON = "json"
+
+	ruleDocumentVersion = 1
+)
+
+type RuleDocument struct {
+	Version int              `json:"version" yaml:"version"`
+	Rules   []RuleDefinition `json:"rules" yaml:"rules"`
+}
+
+type RuleDefinition struct {
+	Name           string                `json:"name" yaml:"name"`
+	Priority       *int                  `json:"priority,omitempty" yaml:"priority,omitempty"`
+	Enabled        *bool                 `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	StopProcessing bool                  `json:"stopProcessing,omitempty" yaml:"stopProcessing,omitempty"`
+	HostPrefix     string                `json:"hostPrefix,omitempty" yaml:"hostPrefix,omitempty"`
+	URLPrefix      string                `json:"urlPrefix,omitempty" yaml:"urlPrefix,omitempty"`
+	PathPrefix     string                `json:"pathPrefix,omitempty" yaml:"pathPrefix,omitempty"`
+	TitleContains  string                `json:"titleContains,omitempty" yaml:"titleContains,omitempty"`
+	Conditions     *models.RuleCondition `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	Actions        *models.RuleActions   `json:"actions,omitempty" yaml:"actions,omitempty"`
+	Category       string                `json:"category,omitempty" yaml:"category,omitempty"`
+	Tags           []string              `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+type RuleImportOptions struct {
+	DryRun bool
+}
+
+type RuleImportItem struct {
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	RuleID string `json:"ruleId,omitempty"`
+}
+
+type RuleImportReport struct {
+	DryRun    bool             `json:"dryRun"`
+	Created   int              `json:"created"`
+	Updated   int              `json:"updated"`
+	Unchanged int              `json:"unchanged"`
+	Items     []RuleImportItem `json:"items"`
+}
+
+func EncodeRuleDocument(document *RuleDocument, format string) ([]byte, error) {
+	switch format {
+	case RuleFormatJSON:
+		return json.MarshalIndent(document, "", "  ")
+	case RuleFormatYAML, "":
+		var buffer bytes.Buffer
+		encoder := yaml.NewEncoder(&buffer)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(document); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+		return buffer.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+func DecodeRuleDocument(data []byte, format string) (*RuleDocument, error) {
+	var document RuleDocument
+	switch format {
+	case RuleFormatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&document); err != nil {
+			return nil, fmt.Errorf("invalid rule document: %w", err)
+		}
+	case RuleFormatYAML, "":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&document); err != nil {
+			return nil, fmt.Errorf("invalid rule document: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	if document.Version > ruleDocumentVersion {
+		return nil, fmt.Errorf("unsupported rule document version %d", document.Version)
+	}
+	return &document, nil
+}
+
+func (service *RuleService) Export(ctx context.Context) (*RuleDocument, error) {
+	rules, err := service.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	document := &RuleDocument{Version: ruleDocumentVersion, Rules: []RuleDefinition{}}
+	for _, rule := range rules {
+		document.Rules = append(document.Rules, ruleDefinition(rule))
+	}
+	return document, nil
+}
+
+func (service *RuleService) Import(ctx context.Context, document *RuleDocument, options RuleImportOptions) (*RuleImportReport, error) {
+	if document == nil || len(document.Rules) == 0 {
+		return nil, errors.New("rules are required")
+	}
+
+	seen := map[string]struct{}{}
+	for index, definition := range document.Rules {
+		key := ruleNameKey(definition.Name)
+		if err := validateRuleInput(definition.input()); err != nil {
+			return nil, fmt.Errorf("rule %d (%s): %w", index+1, strings.TrimSpace(definition.Name), err)
+		}
+		if _, exists := seen[key]; exists {
+			return nil, fmt.Errorf("rule %q appears more than once", strings.TrimSpace(definition.Name))
+		}
+		seen[key] = struct{}{}
+	}
+
+	existingRules, err := service.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]models.Rule{}
+	for _, rule := range existingRules {
+		key := ruleNameKey(rule.Name)
+		if _, exists := existing[key]; !exists {
+			existing[key] = rule
+		}
+	}
+
+	report := &RuleImportReport{DryRun: options.DryRun, Items: []RuleImportItem{}}
+	for _, definition := range document.Rules {
+		item := RuleImportItem{Name: strings.TrimSpace(definition.Name)}
+		tags, err := resolveTagAliases(ctx, service.Pool, normalizeTags(definition.Tags))
+		if err != nil {
+			return nil, err
+		}
+
+		current, exists := existing[ruleNameKey(definition.Name)]
+		switch {
+		case !exists:
+			item.Action = "create"
+			report.Created++
+		case definition.sameAs(current, tags):
+			item.Action = "unchanged"
+			item.RuleID = current.ID
+			report.Unchanged++
+		default:
+			item.Action = "update"
+			item.RuleID = current.ID
+			report.Updated++
+		}
+		report.Items = append(report.Items, item)
+	}
+
+	if options.DryRun {
+		return report, nil
+	}
+
+	tx, err := service.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	for index, definition := range document.Rules {
+		item := &report.Items[index]
+		switch item.Action {
+		case "create":
+			ruleID, err := insertRule(ctx, tx, definition.input())
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: %w", item.Name, err)
+			}
+			item.RuleID = ruleID
+		case "update":
+			if err := updateRule(ctx, tx, item.RuleID, definition.input()); err != nil {
+				return nil, fmt.Errorf("rule %q: %w", item.Name, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	service.RuleCache.Invalidate()
+	return report, nil
+}
+
+func ruleDefinition(rule models.Rule) RuleDefinition {
+	priority := rule.Priority
+	enabled := rule.Enabled
+	definition := RuleDefinition{
+		Name:           rule.Name,
+		Priority:       &priority,
+		Enabled:        &enabled,
+		StopProcessing: rule.StopProcessing,
+		HostPrefix:     rule.HostPrefix,
+		URLPrefix:      rule.URLPrefix,
+		PathPrefix:     rule.PathPrefix,
+		TitleContains:  rule.TitleContains,
+		Conditions:     rule.Conditions,
+		Actions:        rule.Actions,
+	}
+	if rule.CategoryName != nil {
+		definition.Category = *rule.CategoryName
+	}
+	for _, tag := range rule.Tags {
+		definition.Tags = append(definition.Tags, tag.Name)
+	}
+	return definition
+}
+
+func (definition RuleDefinition) input() RuleInput {
+	return RuleInput{
+		Name:           definition.Name,
+		HostPrefix:     definition.HostPrefix,
+		URLPrefix:      definition.URLPrefix,
+		PathPrefix:     definition.PathPrefix,
+		TitleContains:  definition.TitleContains,
+		Conditions:     definition.Conditions,
+		Priority:       definition.Priority,
+		Enabled:        definition.Enabled,
+		StopProcessing: &definition.StopProcessing,
+		Actions:        definition.Actions,
+		Category:       definition.Category,
+		Tags:           definition.Tags,
+	}
+}
+
+func (definition RuleDefinition) sameAs(rule models.Rule, tags []string) bool {
+	if strings.TrimSpace(definition.Name) != rule.Name ||
+		strings.TrimSpace(definition.HostPrefix) != rule.HostPrefix ||
+		strings.TrimSpace(definition.URLPrefix) != rule.URLPrefix ||
+		strings.TrimSpace(definition.PathPrefix) != rule.PathPrefix ||
+		strings.TrimSpace(definition.TitleContains) != rule.TitleContains ||
+		definition.StopProcessing != rule.StopProcessing {
+		return false
+	}
+	if definition.Priority != nil && *definition.Priority != rule.Priority {
+		return false
+	}
+	if definition.Enabled != nil && *definition.Enabled != rule.Enabled {
+		return false
+	}
+
+	category := ""
+	if rule.CategoryName != nil {
+		category = *rule.CategoryName
+	}
+	if utils.NormalizeName(definition.Category) != category {
+		return false
+	}
+	if !sameRuleCondition(definition.Conditions, rule.Conditions) || !sameRuleActions(definition.Actions, rule.Actions) {
+		return false
+	}
+
+	current := make([]string, 0, len(rule.Tags))
+	for _, tag := range rule.Tags {
+		current = append(current, tag.Name)
+	}
+	incoming := slices.Clone(tags)
+	slices.Sort(current)
+	slices.Sort(incoming)
+	return slices.Equal(current, incoming)
+}
+
+func sameRuleCondition(left *models.RuleCondition, right *models.RuleCondition) bool {
+	if left == nil {
+		left = &models.RuleCondition{}
+	}
+	if right == nil {
+		right = &models.RuleCondition{}
+	}
+	if left.Field != right.Field || left.Op != right.Op || left.Value != right.Value || left.Negate != right.Negate {
+		return false
+	}
+	return sameRuleConditions(left.All, right.All) && sameRuleConditions(left.Any, right.Any)
+}
+
+func sameRuleConditions(left []models.RuleCondition, right []models.RuleCondition) bool {
+	if len(left) != len(right) {
+		return false
+	}
+	for index := range left {
+		if !sameRuleCondition(&left[index], &right[index]) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameRuleActions(left *models.RuleActions, right *models.RuleActions) bool {
+	if left == nil {
+		left = &models.RuleActions{}
+	}
+	if right == nil {
+		right = &models.RuleActions{}
+	}
+	return slices.Equal(normalizeTags(left.RemoveTags), normalizeTags(right.RemoveTags)) &&
+		left.TitlePattern == right.TitlePattern &&
+		left.TitleReplace == right.TitleReplace &&
+		left.URLPattern == right.URLPattern &&
+		left.URLReplace == right.URLReplace &&
+		left.ReadLater == right.ReadLater &&
+		left.Reject == right.Reject &&
+		left.RejectReason == right.RejectReason
+}
+
+func ruleNameKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
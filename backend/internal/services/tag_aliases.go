@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"bookmarks-backend/internal/models"
+	"bookmarks-backend/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type tagAliasQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+func resolveTagAliases(ctx context.Context, db tagAliasQuerier, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return names, nil
+	}
+
+	lookup := []string{}
+	for _, name := range names {
+		lookup = append(lookup, name)
+		lookup = append(lookup, utils.TagAncestors(name)...)
+	}
+
+	rows, err := db.Query(ctx, `
+		SELECT a.alias, t.name
+		FROM tag_aliases a
+		JOIN tags t ON t.id = a.tag_id
+		WHERE a.alias = ANY($1)
+	`, lookup)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	canonical := map[string]string{}
+	for rows.Next() {
+		var alias string
+		var name string
+		if err := rows.Scan(&alias, &name); err != nil {
+			return nil, err
+		}
+		canonical[alias] = name
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(canonical) == 0 {
+		return names, nil
+	}
+
+	unique := map[string]struct{}{}
+	resolved := []string{}
+	for _, name := range names {
+		name = resolveAliasPath(name, canonical)
+		if _, exists := unique[name]; !exists {
+			unique[name] = struct{}{}
+			resolved = append(resolved, name)
+		}
+	}
+	return resolved, nil
+}
+
+func resolveAliasPath(name string, canonical map[string]string) string {
+	if target, ok := canonical[name]; ok {
+		return target
+	}
+	ancestors := utils.TagAncestors(name)
+	for index := len(ancestors) - 1; index >= 0; index-- {
+		if target, ok := canonical[ancestors[index]]; ok {
+			return target + name[len(ancestors[index]):]
+		}
+	}
+	return name
+}
+
+func (service *TagService) ListAliases(ctx context.Context, tagID string) ([]models.TagAlias, error) {
+	var exists bool
+	if err := service.Pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM tags WHERE id = $1)", tagID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.New("tag not found")
+	}
+
+	rows, err := service.Pool.Query(ctx, `
+		SELECT id, tag_id, alias, created_at
+		FROM tag_aliases
+		WHERE tag_id = $1
+		ORDER BY alias ASC
+	`, tagID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	aliases := []models.TagAlias{}
+	for rows.Next() {
+		var alias models.TagAlias
+		if err := rows.Scan(&alias.ID, &alias.TagID, &alias.Alias, &alias.CreatedAt); err != nil {
+			return nil, err
+		}
+		aliases = append(aliases, alias)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return aliases, nil
+}
+
+func (service *TagService) AddAlias(ctx context.Context, tagID string, name string) (*models.TagAlias, error) {
+	cleaned := utils.NormalizeTagName(name)
+	if cleaned == "" {
+		return nil, errors.New("alias is required")
+	}
+
+	tx, err := service.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var tagName string
+	if err := tx.QueryRow(ctx, "SELECT name FROM tags WHERE id = $1", tagID).Scan(&tagName); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("tag not found")
+		}
+		return nil, err
+	}
+	if cleaned == tagName {
+		return nil, errors.New("alias must differ from the tag name")
+	}
+
+	var existingID string
+	err = tx.QueryRow(ctx, "SELECT id FROM tags WHERE name = $1", cleaned).Scan(&existingID)
+	if err == nil {
+		return nil, fmt.Errorf("tag %q already exists; merge the tags instead", cleaned)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	alias := models.TagAlias{TagID: tagID, Alias: cleaned}
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO tag_aliases (alias, tag_id)
+		VALUES ($1, $2)
+		RETURNING id, created_at
+	`, cleaned, tagID).Scan(&alias.ID, &alias.CreatedAt); err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("alias %q is already in use", cleaned)
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &alias, nil
+}
+
+func (service *TagService) DeleteAlias(ctx context.Context, tagID string, aliasID string) error {
+	commandTag, err := service.Pool.Exec(ctx, "DELETE FROM tag_aliases WHERE id = $1 AND tag_id = $2", aliasID, tagID)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("alias not found")
+	}
+	return nil
+}
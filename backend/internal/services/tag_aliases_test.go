@@ -0,0 +1,33 @@
+package services
+
+import "testing"
+
+func TestResolveAliasPath(t *testing.T) {
+	canonical := map[string]string{
+		"k8s":      "kubernetes",
+		"k8s/helm": "helm",
+		"js":       "lang/javascript",
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "go", want: "go"},
+		{name: "k8s", want: "kubernetes"},
+		{name: "k8s/operators", want: "kubernetes/operators"},
+		{name: "k8s/operators/crd", want: "kubernetes/operators/crd"},
+		{name: "k8s/helm", want: "helm"},
+		{name: "k8s/helm/charts", want: "helm/charts"},
+		{name: "js/react", want: "lang/javascript/react"},
+		{name: "k8sx/helm", want: "k8sx/helm"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := resolveAliasPath(test.name, canonical); got != test.want {
+				t.Errorf("resolveAliasPath(%q) = %q, want %q", test.name, got, test.want)
+			}
+		})
+	}
+}
@@ -3,10 +3,15 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 
 	"bookmarks-backend/internal/models"
 	"bookmarks-backend/internal/utils"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -14,11 +19,30 @@ type TagService struct {
 	Pool *pgxpool.Pool
 }
 
+type TagMergeInput struct {
+	TargetID string
+	Target   string
+}
+
+type TagMergeResult struct {
+	Tag       models.Tag `json:"tag"`
+	Bookmarks int64      `json:"bookmarks"`
+	Rules     int64      `json:"rules"`
+}
+
 func (service *TagService) List(ctx context.Context) ([]models.Tag, error) {
 	rows, err := service.Pool.Query(ctx, `
-		SELECT id, name
-		FROM tags
-		ORDER BY name ASC
+		SELECT t.id, t.name, t.display_name, t.color, t.icon, t.description,
+			(
+				SELECT COUNT(*)
+				FROM bookmark_tags bt
+				INNER JOIN bookmarks b ON b.id = bt.bookmark_id
+				WHERE bt.tag_id = t.id AND b.deleted_at IS NULL
+			),
+			(SELECT COUNT(*) FROM rule_tags rt WHERE rt.tag_id = t.id),
+			t.last_used_at
+		FROM tags t
+		ORDER BY t.name ASC
 	`)
 	if err != nil {
 		return nil, err
@@ -27,8 +51,8 @@ func (service *TagService) List(ctx context.Context) ([]models.Tag, error) {
 
 	tags := []models.Tag{}
 	for rows.Next() {
-		var tag models.Tag
-		if err := rows.Scan(&tag.ID, &tag.Name); err != nil {
+		tag := models.Tag{Usage: &models.Usage{}}
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.DisplayName, &tag.Color, &tag.Icon, &tag.Description, &tag.Usage.BookmarkCount, &tag.Usage.RuleCount, &tag.Usage.LastUsedAt); err != nil {
 			return nil, err
 		}
 		tags = append(tags, tag)
@@ -38,51 +62,431 @@ func (service *TagService) List(ctx context.Context) ([]models.Tag, error) {
 }
 
 func (service *TagService) Create(ctx context.Context, name string) (*models.Tag, error) {
-	cleaned := utils.NormalizeName(name)
+	cleaned := utils.NormalizeTagName(name)
 	if cleaned == "" {
 		return nil, errors.New("name is required")
 	}
 
-	var tag models.Tag
-	if err := service.Pool.QueryRow(ctx, `
-		INSERT INTO tags (name)
-		VALUES ($1)
-		ON CONFLICT (name)
-		DO UPDATE SET updated_at = NOW()
-		RETURNING id, name
-	`, cleaned).Scan(&tag.ID, &tag.Name); err != nil {
+	tx, err := service.Pool.Begin(ctx)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback(ctx)
 
-	return &tag, nil
+	tags, err := upsertTags(ctx, tx, []string{cleaned}, []string{name})
+	if err != nil {
+		return nil, err
+	}
+	if display := tagDisplayName(name, tags[0].Name); display != "" {
+		if _, err := tx.Exec(ctx, "UPDATE tags SET display_name = $2 WHERE id = $1 AND display_name = ''", tags[0].ID, display); err != nil {
+			return nil, err
+		}
+	}
+	result, err := updateLabel(ctx, tx, "tags", tags[0].ID, LabelUpdateInput{})
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return result.tag(), nil
 }
 
-func (service *TagService) Rename(ctx context.Context, id string, name string) (*models.Tag, error) {
-	cleaned := utils.NormalizeName(name)
-	if cleaned == "" {
-		return nil, errors.New("name is required")
+func (service *TagService) Update(ctx context.Context, id string, input LabelUpdateInput) (*models.Tag, error) {
+	input, err := cleanLabelInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := service.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if input.Name != nil {
+		cleaned := utils.NormalizeTagName(*input.Name)
+		if cleaned == "" {
+			return nil, errors.New("name is required")
+		}
+		if err := renameTag(ctx, tx, id, cleaned); err != nil {
+			return nil, err
+		}
+		if input.DisplayName == nil {
+			display := tagDisplayName(*input.Name, cleaned)
+			input.DisplayName = &display
+		}
+	}
+
+	result, err := updateLabel(ctx, tx, "tags", id, input)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("tag not found")
+		}
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return result.tag(), nil
+}
+
+func renameTag(ctx context.Context, tx pgx.Tx, id string, cleaned string) error {
+	var current string
+	if err := tx.QueryRow(ctx, "SELECT name FROM tags WHERE id = $1", id).Scan(&current); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errors.New("tag not found")
+		}
+		return err
+	}
+	if cleaned == current {
+		return nil
+	}
+	if strings.HasPrefix(cleaned, current+"/") {
+		return errors.New("cannot move a tag under itself")
+	}
+
+	var aliased bool
+	if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM tag_aliases WHERE alias = $1 AND tag_id <> $2)", cleaned, id).Scan(&aliased); err != nil {
+		return err
+	}
+	if aliased {
+		return fmt.Errorf("%q is an alias of another tag", cleaned)
 	}
 
-	var tag models.Tag
-	if err := service.Pool.QueryRow(ctx, `
+	rows, err := tx.Query(ctx, `
 		UPDATE tags
-		SET name = $1, updated_at = NOW()
-		WHERE id = $2
-		RETURNING id, name
-	`, cleaned, id).Scan(&tag.ID, &tag.Name); err != nil {
+		SET name = $1 || substr(name, length($2) + 1), updated_at = NOW()
+		WHERE name = $2 OR starts_with(name, $2 || '/')
+		RETURNING name
+	`, cleaned, current)
+	if err != nil {
+		return err
+	}
+	renamed, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("tag %q already exists; merge the tags instead", cleaned)
+		}
+		return err
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM tag_aliases WHERE alias = ANY($1)", renamed); err != nil {
+		return err
+	}
+	return ensureTagAncestors(ctx, tx, []string{cleaned})
+}
+
+func (service *TagService) Merge(ctx context.Context, id string, input TagMergeInput) (*TagMergeResult, error) {
+	tx, err := service.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := mergeTag(ctx, tx, id, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func mergeTag(ctx context.Context, tx pgx.Tx, id string, input TagMergeInput) (*TagMergeResult, error) {
+	var name string
+	if err := tx.QueryRow(ctx, "SELECT id, name FROM tags WHERE id = $1", id).Scan(&id, &name); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("tag not found")
+		}
+		return nil, err
+	}
+
+	result := &TagMergeResult{}
+	switch {
+	case input.TargetID != "":
+		if err := tx.QueryRow(ctx, "SELECT id, name FROM tags WHERE id = $1", input.TargetID).Scan(&result.Tag.ID, &result.Tag.Name); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, errors.New("target tag not found")
+			}
+			return nil, err
+		}
+	case utils.NormalizeTagName(input.Target) != "":
+		tags, err := upsertTags(ctx, tx, []string{utils.NormalizeTagName(input.Target)}, []string{input.Target})
+		if err != nil {
+			return nil, err
+		}
+		result.Tag = tags[0]
+	default:
+		return nil, errors.New("target is required")
+	}
+	if result.Tag.ID == id {
+		return nil, errors.New("cannot merge a tag into itself")
+	}
+	if strings.HasPrefix(result.Tag.Name, name+"/") {
+		return nil, errors.New("cannot merge a tag into its own descendant")
+	}
+
+	rows, err := tx.Query(ctx, "SELECT id, name FROM tags WHERE starts_with(name, $1 || '/') ORDER BY name ASC", name)
+	if err != nil {
+		return nil, err
+	}
+	descendants := []models.Tag{}
+	for rows.Next() {
+		var descendant models.Tag
+		if err := rows.Scan(&descendant.ID, &descendant.Name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		descendants = append(descendants, descendant)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	affected, snapshots, err := loadAffectedSnapshots(ctx, tx, `
+		SELECT b.id
+		FROM bookmarks b
+		WHERE EXISTS (
+			SELECT 1
+			FROM bookmark_tags bt
+			INNER JOIN tags t ON t.id = bt.tag_id
+			WHERE bt.bookmark_id = b.id AND (t.id = $1 OR starts_with(t.name, $2 || '/'))
+		)
+		ORDER BY b.id
+		FOR UPDATE
+	`, id, name)
+	if err != nil {
 		return nil, err
 	}
 
-	return &tag, nil
+	if err := moveTag(ctx, tx, id, name, result.Tag, result); err != nil {
+		return nil, err
+	}
+	for _, descendant := range descendants {
+		targetName := result.Tag.Name + strings.TrimPrefix(descendant.Name, name)
+		var target models.Tag
+		err := tx.QueryRow(ctx, "SELECT id, name FROM tags WHERE name = $1", targetName).Scan(&target.ID, &target.Name)
+		if errors.Is(err, pgx.ErrNoRows) {
+			if err := renameDescendant(ctx, tx, descendant, targetName); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := moveTag(ctx, tx, descendant.ID, descendant.Name, target, result); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, bookmarkID := range affected {
+		if err := recordVersion(ctx, tx, bookmarkID, VersionSourceMerge, snapshots[bookmarkID]); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
 }
 
-func (service *TagService) Delete(ctx context.Context, id string) error {
-	commandTag, err := service.Pool.Exec(ctx, "DELETE FROM tags WHERE id = $1", id)
+func renameDescendant(ctx context.Context, tx pgx.Tx, tag models.Tag, name string) error {
+	if err := ensureTagAncestors(ctx, tx, []string{name}); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "UPDATE tags SET name = $2, updated_at = NOW() WHERE id = $1", tag.ID, name); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM tag_aliases WHERE alias = $1", name); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO tag_aliases (alias, tag_id)
+		VALUES ($1, $2)
+		ON CONFLICT (alias)
+		DO UPDATE SET tag_id = EXCLUDED.tag_id
+	`, tag.Name, tag.ID)
+	return err
+}
+
+func moveTag(ctx context.Context, tx pgx.Tx, id string, name string, target models.Tag, result *TagMergeResult) error {
+	commandTag, err := tx.Exec(ctx, `
+		INSERT INTO bookmark_tags (bookmark_id, tag_id)
+		SELECT bookmark_id, $2 FROM bookmark_tags WHERE tag_id = $1
+		ON CONFLICT DO NOTHING
+	`, id, target.ID)
 	if err != nil {
 		return err
 	}
-	if commandTag.RowsAffected() == 0 {
-		return errors.New("tag not found")
+	result.Bookmarks += commandTag.RowsAffected()
+
+	commandTag, err = tx.Exec(ctx, `
+		INSERT INTO rule_tags (rule_id, tag_id)
+		SELECT rule_id, $2 FROM rule_tags WHERE tag_id = $1
+		ON CONFLICT DO NOTHING
+	`, id, target.ID)
+	if err != nil {
+		return err
+	}
+	result.Rules += commandTag.RowsAffected()
+
+	if _, err := tx.Exec(ctx, "UPDATE tag_aliases SET tag_id = $2 WHERE tag_id = $1 AND alias <> $3", id, target.ID, target.Name); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM tags WHERE id = $1", id); err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO tag_aliases (alias, tag_id)
+		VALUES ($1, $2)
+		ON CONFLICT (alias)
+		DO UPDATE SET tag_id = EXCLUDED.tag_id
+	`, name, target.ID)
+	return err
+}
+
+func (service *TagService) DeleteOrphans(ctx context.Context) ([]string, error) {
+	rows, err := service.Pool.Query(ctx, `
+		DELETE FROM tags t
+		WHERE NOT EXISTS (
+			SELECT 1
+			FROM tags d
+			WHERE (d.id = t.id OR starts_with(d.name, t.name || '/'))
+				AND (
+					EXISTS (SELECT 1 FROM bookmark_tags bt WHERE bt.tag_id = d.id)
+					OR EXISTS (SELECT 1 FROM rule_tags rt WHERE rt.tag_id = d.id)
+				)
+		)
+		AND NOT EXISTS (SELECT 1 FROM tag_aliases a WHERE a.tag_id = t.id)
+		RETURNING t.name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+func (service *TagService) Delete(ctx context.Context, id string, options DeleteOptions) (*DeleteImpact, error) {
+	if options.Preview {
+		impact, err := tagDeleteImpact(ctx, service.Pool, id)
+		if err != nil {
+			return nil, err
+		}
+		impact.Preview = true
+		if options.ReassignTo != "" {
+			name, err := tagReassignTarget(ctx, service.Pool, id, options.ReassignTo)
+			if err != nil {
+				return nil, err
+			}
+			impact.ReassignedTo = &name
+		} else {
+			impact.FlaggedRules = int64(len(impact.Rules))
+		}
+		return impact, nil
+	}
+
+	tx, err := service.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	defer tx.Rollback(ctx)
+
+	impact, err := tagDeleteImpact(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.ReassignTo != "" {
+		targetID, target := reassignTarget(options.ReassignTo)
+		result, err := mergeTag(ctx, tx, id, TagMergeInput{TargetID: targetID, Target: target})
+		if err != nil {
+			return nil, err
+		}
+		impact.ReassignedTo = &result.Tag.Name
+	} else {
+		impact.FlaggedRules, err = flagRules(ctx, tx, impact.Rules, deletedNote("tag", impact.Name))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(ctx, "DELETE FROM tags WHERE id = $1", id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return impact, nil
+}
+
+func tagDeleteImpact(ctx context.Context, db deleteImpactQuerier, id string) (*DeleteImpact, error) {
+	impact := &DeleteImpact{}
+	if err := db.QueryRow(ctx, "SELECT name, (SELECT COUNT(*) FROM bookmark_tags WHERE tag_id = $1) FROM tags WHERE id = $1", id).Scan(&impact.Name, &impact.Bookmarks); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("tag not found")
+		}
+		return nil, err
+	}
+	rules, err := loadRuleReferences(ctx, db, `
+		SELECT r.id, r.name
+		FROM rules r
+		INNER JOIN rule_tags rt ON rt.rule_id = r.id
+		WHERE rt.tag_id = $1
+		ORDER BY r.priority ASC, r.created_at ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	impact.Rules = rules
+	return impact, nil
+}
+
+func tagReassignTarget(ctx context.Context, db deleteImpactQuerier, id string, reassignTo string) (string, error) {
+	targetID, target := reassignTarget(reassignTo)
+	var name string
+	if targetID != "" {
+		if err := db.QueryRow(ctx, "SELECT name FROM tags WHERE id = $1", targetID).Scan(&name); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return "", errors.New("target tag not found")
+			}
+			return "", err
+		}
+	} else {
+		names, err := resolveTagAliases(ctx, db, normalizeTags([]string{target}))
+		if err != nil {
+			return "", err
+		}
+		if len(names) == 0 {
+			return "", errors.New("target is required")
+		}
+		name = names[0]
+		if err := db.QueryRow(ctx, "SELECT id FROM tags WHERE name = $1", name).Scan(&targetID); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return "", err
+		}
+	}
+	if targetID == id {
+		return "", errors.New("cannot merge a tag into itself")
+	}
+	var source string
+	if err := db.QueryRow(ctx, "SELECT name FROM tags WHERE id = $1", id).Scan(&source); err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(name, source+"/") {
+		return "", errors.New("cannot merge a tag into its own descendant")
+	}
+	return name, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
 }
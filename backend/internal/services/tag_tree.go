@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"bookmarks-backend/internal/models"
+	"bookmarks-backend/internal/utils"
+)
+
+func (service *TagService) Tree(ctx context.Context) ([]*models.TagNode, error) {
+	rows, err := service.Pool.Query(ctx, `
+		SELECT t.id, t.name, bt.bookmark_id
+		FROM tags t
+		LEFT JOIN (
+			bookmark_tags bt
+			INNER JOIN bookmarks b ON b.id = bt.bookmark_id AND b.deleted_at IS NULL
+		) ON bt.tag_id = t.id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	nodes := map[string]*models.TagNode{}
+	bookmarks := map[string]map[string]struct{}{}
+	node := func(name string) *models.TagNode {
+		if existing := nodes[name]; existing != nil {
+			return existing
+		}
+		created := &models.TagNode{Name: name, Label: name[strings.LastIndex(name, "/")+1:], Children: []*models.TagNode{}}
+		nodes[name] = created
+		bookmarks[name] = map[string]struct{}{}
+		return created
+	}
+
+	for rows.Next() {
+		var id string
+		var name string
+		var bookmarkID *string
+		if err := rows.Scan(&id, &name, &bookmarkID); err != nil {
+			return nil, err
+		}
+		current := node(name)
+		current.ID = id
+		if bookmarkID == nil {
+			continue
+		}
+		current.BookmarkCount++
+		bookmarks[name][*bookmarkID] = struct{}{}
+		for _, ancestor := range utils.TagAncestors(name) {
+			node(ancestor)
+			bookmarks[ancestor][*bookmarkID] = struct{}{}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	for _, name := range names {
+		for _, ancestor := range utils.TagAncestors(name) {
+			node(ancestor)
+		}
+	}
+
+	roots := []*models.TagNode{}
+	for name, current := range nodes {
+		current.TotalCount = len(bookmarks[name])
+		if index := strings.LastIndex(name, "/"); index >= 0 {
+			parent := nodes[name[:index]]
+			parent.Children = append(parent.Children, current)
+			continue
+		}
+		roots = append(roots, current)
+	}
+	for _, current := range nodes {
+		sortTagNodes(current.Children)
+	}
+	sortTagNodes(roots)
+	return roots, nil
+}
+
+func sortTagNodes(nodes []*models.TagNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+}
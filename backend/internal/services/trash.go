@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"bookmarks-backend/internal/models"
+)
+
+func (service *BookmarkService) ListTrash(ctx context.Context, page int, pageSize int) (*models.BookmarkListResponse, error) {
+	page = max(page, 1)
+	pageSize = max(pageSize, 1)
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	var total int
+	if err := service.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM bookmarks WHERE deleted_at IS NOT NULL").Scan(&total); err != nil {
+		return nil, err
+	}
+
+	rows, err := service.Pool.Query(ctx, `
+		SELECT b.id, b.url, b.normalized_url, b.title, b.description, b.category_id,
+		c.name, b.canonical_url, b.read_later, b.created_at, b.updated_at, b.deleted_at
+		FROM bookmarks b
+		LEFT JOIN categories c ON c.id = b.category_id
+		WHERE b.deleted_at IS NOT NULL
+		ORDER BY b.deleted_at DESC
+		LIMIT $1 OFFSET $2
+	`, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bookmarks := []models.Bookmark{}
+	for rows.Next() {
+		bookmark := models.Bookmark{}
+		if err := rows.Scan(&bookmark.ID, &bookmark.URL, &bookmark.NormalizedURL, &bookmark.Title, &bookmark.Description, &bookmark.CategoryID, &bookmark.CategoryName, &bookmark.CanonicalURL, &bookmark.ReadLater, &bookmark.CreatedAt, &bookmark.UpdatedAt, &bookmark.DeletedAt); err != nil {
+			return nil, err
+		}
+		bookmarks = append(bookmarks, bookmark)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for index := range bookmarks {
+		tags, err := service.fetchTags(ctx, bookmarks[index].ID)
+		if err != nil {
+			return nil, err
+		}
+		bookmarks[index].Tags = tags
+	}
+
+	return &models.BookmarkListResponse{
+		Items: bookmarks,
+		Pagination: models.Pagination{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		},
+	}, nil
+}
+
+func (service *BookmarkService) Restore(ctx context.Context, id string) (*models.Bookmark, error) {
+	tx, err := service.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := loadSnapshot(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	commandTag, err := tx.Exec(ctx, `
+		UPDATE bookmarks
+		SET deleted_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return nil, errors.New("bookmark not found in trash")
+	}
+	if err := recordVersion(ctx, tx, id, VersionSourceRestore, before); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return service.Get(ctx, id)
+}
+
+func (service *BookmarkService) DeletePermanently(ctx context.Context, id string) error {
+	commandTag, err := service.Pool.Exec(ctx, "DELETE FROM bookmarks WHERE id = $1 AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("bookmark not found in trash")
+	}
+	return nil
+}
+
+func (service *BookmarkService) EmptyTrash(ctx context.Context) (int64, error) {
+	commandTag, err := service.Pool.Exec(ctx, "DELETE FROM bookmarks WHERE deleted_at IS NOT NULL")
+	if err != nil {
+		return 0, err
+	}
+	return commandTag.RowsAffected(), nil
+}
+
+func (service *BookmarkService) PurgeTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	commandTag, err := service.Pool.Exec(ctx, "DELETE FROM bookmarks WHERE deleted_at < $1", time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+	return commandTag.RowsAffected(), nil
+}
+
+func (service *BookmarkService) StartTrashPurger(ctx context.Context, retention time.Duration, interval time.Duration) {
+	if retention <= 0 || interval <= 0 {
+		return
+	}
+
+	purge := func() {
+		purged, err := service.PurgeTrash(ctx, retention)
+		if err != nil {
+			log.Printf("trash purge error: %v", err)
+			return
+		}
+		if purged > 0 {
+			log.Printf("trash purge: %d bookmarks deleted", purged)
+		}
+	}
+
+	go func() {
+		purge()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				purge()
+			}
+		}
+	}()
+}
@@ -0,0 +1,249 @@
+package utils
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type MetadataExtractor interface {
+	HostPatterns() []string
+	Extract(pageURL *url.URL, doc *goquery.Document, metadata *Metadata)
+}
+
+var (
+	extractorsMu       sync.RWMutex
+	metadataExtractors []MetadataExtractor
+)
+
+func init() {
+	RegisterMetadataExtractor(githubExtractor{})
+	RegisterMetadataExtractor(youtubeExtractor{})
+	RegisterMetadataExtractor(arxivExtractor{})
+	RegisterMetadataExtractor(hackerNewsExtractor{})
+	RegisterMetadataExtractor(stackOverflowExtractor{})
+}
+
+func RegisterMetadataExtractor(extractor MetadataExtractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	metadataExtractors = append(metadataExtractors, extractor)
+}
+
+func FindMetadataExtractor(host string) MetadataExtractor {
+	host = strings.ToLower(host)
+	extractorsMu.RLock()
+	defer extractorsMu.RUnlock()
+	for _, extractor := range metadataExtractors {
+		for _, pattern := range extractor.HostPatterns() {
+			if MatchHostPattern(pattern, host) {
+				return extractor
+			}
+		}
+	}
+	return nil
+}
+
+func MatchHostPattern(pattern string, host string) bool {
+	pattern = strings.ToLower(pattern)
+	if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+		return host == suffix || strings.HasSuffix(host, "."+suffix)
+	}
+	return host == pattern
+}
+
+func metaContent(doc *goquery.Document, selector string) string {
+	return strings.TrimSpace(doc.Find(selector).First().AttrOr("content", ""))
+}
+
+func pathSegments(pageURL *url.URL) []string {
+	segments := []string{}
+	for _, segment := range strings.Split(pageURL.Path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+type githubExtractor struct{}
+
+var githubDescriptionSuffix = regexp.MustCompile(`\s*Contribute to [^ ]+ development by creating an account on GitHub\.?$`)
+
+func (githubExtractor) HostPatterns() []string {
+	return []string{"github.com", "www.github.com"}
+}
+
+func (githubExtractor) Extract(pageURL *url.URL, doc *goquery.Document, metadata *Metadata) {
+	segments := pathSegments(pageURL)
+	if len(segments) != 2 {
+		return
+	}
+
+	metadata.Title = segments[0] + "/" + segments[1]
+	description := metaContent(doc, `meta[property="og:description"]`)
+	if description == "" {
+		description = metadata.Description
+	}
+	metadata.Description = strings.TrimSpace(githubDescriptionSuffix.ReplaceAllString(description, ""))
+	metadata.Category = "development"
+
+	language := strings.TrimSpace(doc.Find(`[itemprop="programmingLanguage"]`).First().Text())
+	if language == "" {
+		language = strings.TrimSpace(doc.Find(`a[href*="search?l="] span.text-bold`).First().Text())
+	}
+	if language != "" {
+		metadata.SetProperty("language", language)
+		metadata.Tags = append(metadata.Tags, language)
+	}
+
+	doc.Find("a.topic-tag").Each(func(_ int, selection *goquery.Selection) {
+		if topic := strings.TrimSpace(selection.Text()); topic != "" {
+			metadata.Tags = append(metadata.Tags, topic)
+		}
+	})
+}
+
+type youtubeExtractor struct{}
+
+var isoDurationPattern = regexp.MustCompile(`^PT(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?$`)
+
+func (youtubeExtractor) HostPatterns() []string {
+	return []string{"*.youtube.com", "youtu.be"}
+}
+
+func (youtubeExtractor) Extract(pageURL *url.URL, doc *goquery.Document, metadata *Metadata) {
+	if title := metaContent(doc, `meta[property="og:title"]`); title != "" {
+		metadata.Title = title
+	} else {
+		metadata.Title = strings.TrimSuffix(metadata.Title, " - YouTube")
+	}
+	if description := metaContent(doc, `meta[property="og:description"]`); description != "" {
+		metadata.Description = description
+	}
+	metadata.Category = "video"
+
+	if channel := strings.TrimSpace(doc.Find(`span[itemprop="author"] link[itemprop="name"]`).First().AttrOr("content", "")); channel != "" {
+		metadata.SetProperty("channel", channel)
+	}
+	if duration := formatISODuration(metaContent(doc, `meta[itemprop="duration"]`)); duration != "" {
+		metadata.SetProperty("duration", duration)
+	}
+}
+
+func formatISODuration(value string) string {
+	parts := isoDurationPattern.FindStringSubmatch(value)
+	if parts == nil {
+		return ""
+	}
+	hours, minutes, seconds := parts[1], parts[2], parts[3]
+	if minutes == "" {
+		minutes = "0"
+	}
+	if seconds == "" {
+		seconds = "0"
+	}
+	if len(seconds) == 1 {
+		seconds = "0" + seconds
+	}
+	if hours != "" {
+		if len(minutes) == 1 {
+			minutes = "0" + minutes
+		}
+		return hours + ":" + minutes + ":" + seconds
+	}
+	return minutes + ":" + seconds
+}
+
+type arxivExtractor struct{}
+
+var arxivSubjectPattern = regexp.MustCompile(`\(([a-z\-]+(?:\.[A-Z]{2})?)\)`)
+
+func (arxivExtractor) HostPatterns() []string {
+	return []string{"arxiv.org", "www.arxiv.org", "export.arxiv.org"}
+}
+
+func (arxivExtractor) Extract(pageURL *url.URL, doc *goquery.Document, metadata *Metadata) {
+	if title := metaContent(doc, `meta[name="citation_title"]`); title != "" {
+		metadata.Title = title
+	}
+	if abstract := metaContent(doc, `meta[name="citation_abstract"]`); abstract != "" {
+		metadata.Description = abstract
+	}
+	metadata.Category = "papers"
+
+	authors := []string{}
+	doc.Find(`meta[name="citation_author"]`).Each(func(_ int, selection *goquery.Selection) {
+		if author := strings.TrimSpace(selection.AttrOr("content", "")); author != "" {
+			authors = append(authors, author)
+		}
+	})
+	if len(authors) > 0 {
+		metadata.SetProperty("authors", strings.Join(authors, "; "))
+	}
+
+	subject := strings.TrimSpace(doc.Find("span.primary-subject").First().Text())
+	if match := arxivSubjectPattern.FindStringSubmatch(subject); match != nil {
+		metadata.Tags = append(metadata.Tags, match[1])
+	}
+}
+
+type hackerNewsExtractor struct{}
+
+func (hackerNewsExtractor) HostPatterns() []string {
+	return []string{"news.ycombinator.com"}
+}
+
+func (hackerNewsExtractor) Extract(pageURL *url.URL, doc *goquery.Document, metadata *Metadata) {
+	if pageURL.Path != "/item" {
+		return
+	}
+
+	link := doc.Find("span.titleline > a").First()
+	if title := strings.TrimSpace(link.Text()); title != "" {
+		metadata.Title = title
+	}
+	if href := strings.TrimSpace(link.AttrOr("href", "")); href != "" {
+		if resolved, err := pageURL.Parse(href); err == nil {
+			metadata.SetProperty("link", resolved.String())
+		}
+	}
+	if score := strings.TrimSpace(doc.Find("span.score").First().Text()); score != "" {
+		metadata.SetProperty("points", strings.TrimSuffix(score, " points"))
+	}
+	metadata.Category = "news"
+	metadata.Tags = append(metadata.Tags, "hacker-news")
+}
+
+type stackOverflowExtractor struct{}
+
+func (stackOverflowExtractor) HostPatterns() []string {
+	return []string{"stackoverflow.com", "*.stackexchange.com", "superuser.com", "serverfault.com", "askubuntu.com"}
+}
+
+func (stackOverflowExtractor) Extract(pageURL *url.URL, doc *goquery.Document, metadata *Metadata) {
+	segments := pathSegments(pageURL)
+	if len(segments) < 2 || segments[0] != "questions" {
+		return
+	}
+
+	if title := strings.TrimSpace(doc.Find("#question-header h1").First().Text()); title != "" {
+		metadata.Title = title
+	}
+	metadata.Category = "development"
+
+	if votes := strings.TrimSpace(doc.Find(".question .js-vote-count").First().AttrOr("data-value", "")); votes != "" {
+		metadata.SetProperty("votes", votes)
+	}
+	if doc.Find(".accepted-answer").Length() > 0 {
+		metadata.SetProperty("answered", "true")
+	}
+	doc.Find(".question .post-taglist a.post-tag").Each(func(_ int, selection *goquery.Selection) {
+		if tag := strings.TrimSpace(selection.Text()); tag != "" {
+			metadata.Tags = append(metadata.Tags, tag)
+		}
+	})
+}
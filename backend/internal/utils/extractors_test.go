@@ -0,0 +1,51 @@
+package utils
+
+import "testing"
+
+func TestFormatISODuration(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "PT4M13S", want: "4:13"},
+		{input: "PT45S", want: "0:45"},
+		{input: "PT10M", want: "10:00"},
+		{input: "PT1H2M3S", want: "1:02:03"},
+		{input: "PT2H", want: "2:00:00"},
+		{input: "PT1H30M", want: "1:30:00"},
+		{input: "P1DT2H", want: ""},
+		{input: "4:13", want: ""},
+		{input: "", want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			if got := formatISODuration(test.input); got != test.want {
+				t.Errorf("formatISODuration(%q) = %q, want %q", test.input, got, test.want)
+			}
+		})
+	}
+}
+
+func TestMatchHostPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		host    string
+		want    bool
+	}{
+		{pattern: "github.com", host: "github.com", want: true},
+		{pattern: "GitHub.com", host: "github.com", want: true},
+		{pattern: "github.com", host: "gist.github.com", want: false},
+		{pattern: "*.youtube.com", host: "youtube.com", want: true},
+		{pattern: "*.youtube.com", host: "www.youtube.com", want: true},
+		{pattern: "*.youtube.com", host: "notyoutube.com", want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern+" "+test.host, func(t *testing.T) {
+			if got := MatchHostPattern(test.pattern, test.host); got != test.want {
+				t.Errorf("MatchHostPattern(%q, %q) = %v, want %v", test.pattern, test.host, got, test.want)
+			}
+		})
+	}
+}
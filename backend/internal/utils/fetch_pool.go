@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+type FetchPool struct {
+	Concurrency  int
+	HostInterval time.Duration
+	MaxRetries   int
+	BaseBackoff  time.Duration
+
+	once       sync.Once
+	slots      chan struct{}
+	mu         sync.Mutex
+	nextByHost map[string]time.Time
+}
+
+func NewFetchPool(concurrency int, hostInterval time.Duration, maxRetries int) *FetchPool {
+	return &FetchPool{
+		Concurrency:  concurrency,
+		HostInterval: hostInterval,
+		MaxRetries:   maxRetries,
+		BaseBackoff:  500 * time.Millisecond,
+	}
+}
+
+func (pool *FetchPool) Size() int {
+	if pool.Concurrency <= 0 {
+		return 1
+	}
+	return pool.Concurrency
+}
+
+func (pool *FetchPool) Fetch(ctx context.Context, targetURL string, conditional ConditionalHeaders) (*Metadata, error) {
+	pool.once.Do(func() {
+		pool.slots = make(chan struct{}, pool.Size())
+		pool.nextByHost = map[string]time.Time{}
+	})
+
+	host := ""
+	if parsed, err := url.Parse(targetURL); err == nil {
+		host = strings.ToLower(parsed.Hostname())
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := pool.waitForHost(ctx, host); err != nil {
+			return nil, err
+		}
+
+		select {
+		case pool.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		metadata, err := FetchMetadataConditional(ctx, targetURL, conditional)
+		<-pool.slots
+
+		if err == nil || attempt >= pool.MaxRetries || !isRetryableFetch(metadata, err) {
+			return metadata, err
+		}
+
+		select {
+		case <-time.After(pool.backoff(attempt)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (pool *FetchPool) waitForHost(ctx context.Context, host string) error {
+	if host == "" || pool.HostInterval <= 0 {
+		return nil
+	}
+
+	pool.mu.Lock()
+	now := time.Now()
+	slot := pool.nextByHost[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	pool.nextByHost[host] = slot.Add(pool.HostInterval)
+	pool.mu.Unlock()
+
+	wait := time.Until(slot)
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (pool *FetchPool) backoff(attempt int) time.Duration {
+	base := pool.BaseBackoff
+	if base <= 0 {
+		base = 500 * time.Millisecond
+	}
+	delay := base << attempt
+	return delay + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
+
+func isRetryableFetch(metadata *Metadata, err error) bool {
+	if metadata != nil {
+		return metadata.StatusCode == http.StatusTooManyRequests || metadata.StatusCode >= http.StatusInternalServerError
+	}
+
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return false
+	}
+	if urlErr.Timeout() {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(urlErr.Err, &dnsErr) && dnsErr.IsNotFound {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(urlErr.Err, &netErr) || errors.Is(urlErr.Err, io.EOF) || errors.Is(urlErr.Err, io.ErrUnexpectedEOF)
+}
+
+func InterleaveByHost(urls []string) []string {
+	order := []string{}
+	byHost := map[string][]string{}
+	for _, rawURL := range urls {
+		host := ""
+		if parsed, err := url.Parse(rawURL); err == nil {
+			host = strings.ToLower(parsed.Hostname())
+		}
+		if _, exists := byHost[host]; !exists {
+			order = append(order, host)
+		}
+		byHost[host] = append(byHost[host], rawURL)
+	}
+
+	result := make([]string, 0, len(urls))
+	for len(result) < len(urls) {
+		for _, host := range order {
+			if queue := byHost[host]; len(queue) > 0 {
+				result = append(result, queue[0])
+				byHost[host] = queue[1:]
+			}
+		}
+	}
+	return result
+}
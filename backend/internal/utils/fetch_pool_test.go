@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"slices"
+	"syscall"
+	"testing"
+)
+
+func TestInterleaveByHost(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []string
+		want  []string
+	}{
+		{name: "empty", input: []string{}, want: []string{}},
+		{
+			name:  "single host keeps order",
+			input: []string{"https://a.com/1", "https://a.com/2"},
+			want:  []string{"https://a.com/1", "https://a.com/2"},
+		},
+		{
+			name:  "round robin in first-seen host order",
+			input: []string{"https://a.com/1", "https://a.com/2", "https://a.com/3", "https://b.com/1", "https://c.com/1", "https://b.com/2"},
+			want:  []string{"https://a.com/1", "https://b.com/1", "https://c.com/1", "https://a.com/2", "https://b.com/2", "https://a.com/3"},
+		},
+		{
+			name:  "host comparison ignores case and port",
+			input: []string{"https://A.com/1", "https://a.com:8443/2", "https://b.com/1"},
+			want:  []string{"https://A.com/1", "https://b.com/1", "https://a.com:8443/2"},
+		},
+		{
+			name:  "unparseable urls share a bucket",
+			input: []string{"://bad", "https://a.com/1", "%zz"},
+			want:  []string{"://bad", "https://a.com/1", "%zz"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := InterleaveByHost(test.input); !slices.Equal(got, test.want) {
+				t.Errorf("InterleaveByHost() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestIsRetryableFetch(t *testing.T) {
+	wrap := func(err error) error {
+		return &url.Error{Op: "Get", URL: "https://example.com", Err: err}
+	}
+
+	tests := []struct {
+		name     string
+		metadata *Metadata
+		err      error
+		want     bool
+	}{
+		{name: "ok response", metadata: &Metadata{StatusCode: 200}, want: false},
+		{name: "not found", metadata: &Metadata{StatusCode: 404}, want: false},
+		{name: "rate limited", metadata: &Metadata{StatusCode: 429}, want: true},
+		{name: "server error", metadata: &Metadata{StatusCode: 503}, want: true},
+		{name: "timeout", err: wrap(&net.DNSError{Err: "i/o timeout", IsTimeout: true}), want: true},
+		{name: "connection refused", err: wrap(&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}), want: true},
+		{name: "unexpected eof", err: wrap(io.ErrUnexpectedEOF), want: true},
+		{name: "unknown host", err: wrap(&net.DNSError{Err: "no such host", Name: "example.invalid", IsNotFound: true}), want: false},
+		{name: "canceled", err: wrap(context.Canceled), want: false},
+		{name: "invalid url", err: errors.New("parse error"), want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isRetryableFetch(test.metadata, test.err); got != test.want {
+				t.Errorf("isRetryableFetch() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -2,23 +2,56 @@ package utils
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
 type Metadata struct {
-	Title       string
-	Description string
+	Title        string
+	Description  string
+	Category     string
+	Tags         []string
+	Properties   map[string]string
+	FinalURL     string
+	CanonicalURL string
+	ETag         string
+	LastModified string
+	StatusCode   int
+	NotModified  bool
+}
+
+func (metadata *Metadata) SetProperty(key string, value string) {
+	if metadata.Properties == nil {
+		metadata.Properties = map[string]string{}
+	}
+	metadata.Properties[key] = value
+}
+
+type ConditionalHeaders struct {
+	ETag         string
+	LastModified string
 }
 
 func FetchMetadata(ctx context.Context, targetURL string) (*Metadata, error) {
+	return FetchMetadataConditional(ctx, targetURL, ConditionalHeaders{})
+}
+
+func FetchMetadataConditional(ctx context.Context, targetURL string, conditional ConditionalHeaders) (*Metadata, error) {
 	client := &http.Client{Timeout: 10 * time.Second}
 	request, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
 	if err != nil {
 		return nil, err
 	}
+	if conditional.ETag != "" {
+		request.Header.Set("If-None-Match", conditional.ETag)
+	}
+	if conditional.LastModified != "" {
+		request.Header.Set("If-Modified-Since", conditional.LastModified)
+	}
 
 	resp, err := client.Do(request)
 	if err != nil {
@@ -26,14 +59,37 @@ func FetchMetadata(ctx context.Context, targetURL string) (*Metadata, error) {
 	}
 	defer resp.Body.Close()
 
+	metadata := &Metadata{
+		FinalURL:     resp.Request.URL.String(),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		StatusCode:   resp.StatusCode,
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		metadata.NotModified = true
+		return metadata, nil
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return metadata, fmt.Errorf("fetch metadata: unexpected status %d", resp.StatusCode)
+	}
+
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
 		return nil, err
 	}
 
-	metadata := &Metadata{}
 	metadata.Title = doc.Find("title").First().Text()
 	metadata.Description = doc.Find("meta[name=description]").AttrOr("content", "")
 
+	pageURL := resp.Request.URL
+	if href := strings.TrimSpace(doc.Find(`link[rel="canonical"]`).First().AttrOr("href", "")); href != "" {
+		if canonical, err := pageURL.Parse(href); err == nil {
+			metadata.CanonicalURL = canonical.String()
+		}
+	}
+	if extractor := FindMetadataExtractor(pageURL.Hostname()); extractor != nil {
+		extractor.Extract(pageURL, doc, metadata)
+	}
+
 	return metadata, nil
 }
@@ -0,0 +1,196 @@
+package utils
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+)
+
+type HostParamRule struct {
+	Host   string
+	Params []string
+}
+
+type QueryParamRules struct {
+	Strip     []string
+	HostKeep  []HostParamRule
+	HostStrip []HostParamRule
+}
+
+var DefaultStripParams = []string{
+	"utm_*",
+	"fbclid",
+	"gclid",
+	"dclid",
+	"gbraid",
+	"wbraid",
+	"msclkid",
+	"yclid",
+	"igshid",
+	"mc_cid",
+	"mc_eid",
+	"_hsenc",
+	"_hsmi",
+	"ref",
+	"ref_src",
+	"ref_url",
+	"si",
+}
+
+var DefaultHostKeepParams = []HostParamRule{
+	{Host: "youtu.be", Params: []string{"list"}},
+	{Host: "*.youtube.com", Params: []string{"v", "list"}},
+}
+
+var (
+	queryRulesMu sync.RWMutex
+	queryRules   = QueryParamRules{
+		Strip:    DefaultStripParams,
+		HostKeep: DefaultHostKeepParams,
+	}
+)
+
+func SetQueryParamRules(rules QueryParamRules) {
+	rules.HostKeep = sortHostParamRules(rules.HostKeep)
+	rules.HostStrip = sortHostParamRules(rules.HostStrip)
+	queryRulesMu.Lock()
+	defer queryRulesMu.Unlock()
+	queryRules = rules
+}
+
+func ParseQueryParamRules(strip []string, hostRules string) (QueryParamRules, error) {
+	rules := QueryParamRules{
+		Strip:     DefaultStripParams,
+		HostKeep:  slices.Clone(DefaultHostKeepParams),
+		HostStrip: []HostParamRule{},
+	}
+	if len(strip) > 0 {
+		rules.Strip = splitParamNames(strings.Join(strip, ","))
+	}
+
+	for _, entry := range strings.Split(hostRules, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		host, spec, ok := strings.Cut(entry, "=")
+		if !ok {
+			return rules, fmt.Errorf("invalid host param rule %q: expected host=keep:a,b or host=strip:a,b", entry)
+		}
+		mode, params, ok := strings.Cut(spec, ":")
+		if !ok {
+			return rules, fmt.Errorf("invalid host param rule %q: missing keep: or strip:", entry)
+		}
+		host = strings.ToLower(strings.TrimSpace(host))
+		names := splitParamNames(params)
+		switch strings.TrimSpace(mode) {
+		case "keep":
+			rules.HostKeep = setHostParamRule(rules.HostKeep, host, names)
+		case "strip":
+			rules.HostStrip = setHostParamRule(rules.HostStrip, host, names)
+		default:
+			return rules, fmt.Errorf("invalid host param rule %q: unknown mode %q", entry, mode)
+		}
+	}
+	rules.HostKeep = sortHostParamRules(rules.HostKeep)
+	rules.HostStrip = sortHostParamRules(rules.HostStrip)
+	return rules, nil
+}
+
+func setHostParamRule(rules []HostParamRule, host string, params []string) []HostParamRule {
+	for index := range rules {
+		if rules[index].Host == host {
+			rules[index].Params = params
+			return rules
+		}
+	}
+	return append(rules, HostParamRule{Host: host, Params: params})
+}
+
+func sortHostParamRules(rules []HostParamRule) []HostParamRule {
+	sorted := slices.Clone(rules)
+	slices.SortStableFunc(sorted, func(a HostParamRule, b HostParamRule) int {
+		aWildcard := strings.HasPrefix(a.Host, "*.")
+		bWildcard := strings.HasPrefix(b.Host, "*.")
+		if aWildcard != bWildcard {
+			if aWildcard {
+				return 1
+			}
+			return -1
+		}
+		if byLength := cmp.Compare(len(b.Host), len(a.Host)); byLength != 0 {
+			return byLength
+		}
+		return strings.Compare(a.Host, b.Host)
+	})
+	return sorted
+}
+
+func cleanQuery(host string, rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return rawQuery
+	}
+
+	queryRulesMu.RLock()
+	rules := queryRules
+	queryRulesMu.RUnlock()
+
+	var keep []string
+	for _, rule := range rules.HostKeep {
+		if MatchHostPattern(rule.Host, host) {
+			keep = rule.Params
+			break
+		}
+	}
+	strip := rules.Strip
+	for _, rule := range rules.HostStrip {
+		if MatchHostPattern(rule.Host, host) {
+			strip = append(append([]string{}, strip...), rule.Params...)
+		}
+	}
+
+	for key := range values {
+		if keep != nil && !matchesParam(keep, key) {
+			values.Del(key)
+			continue
+		}
+		if matchesParam(strip, key) {
+			values.Del(key)
+		}
+	}
+
+	return values.Encode()
+}
+
+func matchesParam(patterns []string, key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+			continue
+		}
+		if key == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func splitParamNames(value string) []string {
+	names := []string{}
+	for _, name := range strings.Split(value, ",") {
+		if cleaned := strings.ToLower(strings.TrimSpace(name)); cleaned != "" {
+			names = append(names, cleaned)
+		}
+	}
+	return names
+}
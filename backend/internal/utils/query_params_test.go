@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func useQueryParamRules(t *testing.T, rules QueryParamRules) {
+	t.Helper()
+	queryRulesMu.RLock()
+	previous := queryRules
+	queryRulesMu.RUnlock()
+	SetQueryParamRules(rules)
+	t.Cleanup(func() {
+		queryRulesMu.Lock()
+		defer queryRulesMu.Unlock()
+		queryRules = previous
+	})
+}
+
+func TestParseQueryParamRules(t *testing.T) {
+	tests := []struct {
+		name          string
+		strip         []string
+		hostRules     string
+		wantStrip     []string
+		wantHostKeep  []string
+		wantHostStrip []string
+		wantErr       bool
+	}{
+		{
+			name:         "defaults",
+			wantStrip:    DefaultStripParams,
+			wantHostKeep: []string{"youtu.be", "*.youtube.com"},
+		},
+		{
+			name:         "custom strip list",
+			strip:        []string{" UTM_* ", "session,ref"},
+			wantStrip:    []string{"utm_*", "session", "ref"},
+			wantHostKeep: []string{"youtu.be", "*.youtube.com"},
+		},
+		{
+			name:          "exact hosts sort before wildcards and longer hosts first",
+			hostRules:     "*.example.com=strip:session; example.com=keep:id ;news.example.com=keep:p",
+			wantStrip:     DefaultStripParams,
+			wantHostKeep:  []string{"news.example.com", "example.com", "youtu.be", "*.youtube.com"},
+			wantHostStrip: []string{"*.example.com"},
+		},
+		{
+			name:          "overrides a default host rule",
+			hostRules:     "*.youtube.com=keep:v",
+			wantStrip:     DefaultStripParams,
+			wantHostKeep:  []string{"youtu.be", "*.youtube.com"},
+			wantHostStrip: []string{},
+		},
+		{name: "missing equals", hostRules: "example.com", wantErr: true},
+		{name: "missing mode", hostRules: "example.com=id", wantErr: true},
+		{name: "unknown mode", hostRules: "example.com=drop:id", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rules, err := ParseQueryParamRules(test.strip, test.hostRules)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("ParseQueryParamRules(%v, %q) succeeded, want error", test.strip, test.hostRules)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseQueryParamRules(%v, %q) returned error: %v", test.strip, test.hostRules, err)
+			}
+			if !slices.Equal(rules.Strip, test.wantStrip) {
+				t.Errorf("Strip = %v, want %v", rules.Strip, test.wantStrip)
+			}
+			if got := hostParamRuleHosts(rules.HostKeep); !slices.Equal(got, test.wantHostKeep) {
+				t.Errorf("HostKeep hosts = %v, want %v", got, test.wantHostKeep)
+			}
+			if got := hostParamRuleHosts(rules.HostStrip); !slices.Equal(got, test.wantHostStrip) {
+				t.Errorf("HostStrip hosts = %v, want %v", got, test.wantHostStrip)
+			}
+		})
+	}
+}
+
+func TestParseQueryParamRulesDoesNotModifyDefaults(t *testing.T) {
+	if _, err := ParseQueryParamRules(nil, "*.youtube.com=keep:v"); err != nil {
+		t.Fatalf("ParseQueryParamRules returned error: %v", err)
+	}
+	for _, rule := range DefaultHostKeepParams {
+		if rule.Host == "*.youtube.com" && !slices.Equal(rule.Params, []string{"v", "list"}) {
+			t.Fatalf("DefaultHostKeepParams was modified: %v", rule.Params)
+		}
+	}
+}
+
+func TestCleanQuery(t *testing.T) {
+	rules, err := ParseQueryParamRules(nil, "example.com=keep:id,page_*;news.example.com=keep:p;*.example.com=strip:session")
+	if err != nil {
+		t.Fatalf("ParseQueryParamRules returned error: %v", err)
+	}
+	useQueryParamRules(t, rules)
+
+	tests := []struct {
+		name  string
+		host  string
+		query string
+		want  string
+	}{
+		{name: "empty query", host: "example.org", query: "", want: ""},
+		{name: "strips global params", host: "example.org", query: "q=go&utm_source=x&fbclid=y&Ref=z", want: "q=go"},
+		{name: "keep list drops everything else", host: "example.com", query: "id=1&page_size=20&sort=asc&utm_source=x", want: "id=1&page_size=20"},
+		{name: "most specific keep rule wins", host: "news.example.com", query: "p=1&id=2", want: "p=1"},
+		{name: "wildcard strip adds to global list", host: "shop.example.com", query: "session=abc&q=go&utm_medium=x", want: "q=go"},
+		{name: "wildcard strip does not match other domains", host: "example.org", query: "session=abc", want: "session=abc"},
+		{name: "default youtube keep rule still applies", host: "m.youtube.com", query: "v=abc&t=10", want: "v=abc"},
+		{name: "keeps blank values", host: "example.org", query: "flag&q=", want: "flag=&q="},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := cleanQuery(test.host, test.query); got != test.want {
+				t.Errorf("cleanQuery(%q, %q) = %q, want %q", test.host, test.query, got, test.want)
+			}
+		})
+	}
+}
+
+func hostParamRuleHosts(rules []HostParamRule) []string {
+	hosts := []string{}
+	for _, rule := range rules {
+		hosts = append(hosts, rule.Host)
+	}
+	return hosts
+}
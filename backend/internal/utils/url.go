@@ -4,6 +4,10 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
 )
 
 func NormalizeURL(rawURL string) (string, error) {
@@ -21,6 +25,9 @@ func NormalizeURL(rawURL string) (string, error) {
 	}
 
 	host := strings.ToLower(parsed.Hostname())
+	if ascii, err := idna.Lookup.ToASCII(host); err == nil {
+		host = ascii
+	}
 	port := parsed.Port()
 	if (parsed.Scheme == "http" && port == "80") || (parsed.Scheme == "https" && port == "443") {
 		port = ""
@@ -35,6 +42,8 @@ func NormalizeURL(rawURL string) (string, error) {
 	}
 
 	parsed.Host = host
+	parsed.RawQuery = cleanQuery(strings.ToLower(parsed.Hostname()), parsed.RawQuery)
+	parsed.ForceQuery = false
 	parsed.Fragment = ""
 	parsed.Path = path
 	parsed.RawPath = path
@@ -42,6 +51,56 @@ func NormalizeURL(rawURL string) (string, error) {
 	return parsed.String(), nil
 }
 
+func ResolveCanonicalURL(normalizedURL string, finalURL string, canonicalURL string) string {
+	resolved := normalizedURL
+	if finalURL != "" {
+		if normalized, err := NormalizeURL(finalURL); err == nil {
+			resolved = normalized
+		}
+	}
+	if canonicalURL == "" {
+		return resolved
+	}
+
+	normalized, err := NormalizeURL(canonicalURL)
+	if err != nil {
+		return resolved
+	}
+	canonicalParsed, err := url.Parse(normalized)
+	if err != nil {
+		return resolved
+	}
+	resolvedParsed, err := url.Parse(resolved)
+	if err != nil {
+		return resolved
+	}
+	if canonicalParsed.Path == "" && resolvedParsed.Path != "" {
+		return resolved
+	}
+	return normalized
+}
+
 func NormalizeName(name string) string {
-	return strings.ToLower(strings.TrimSpace(name))
+	folded := cases.Fold().String(norm.NFKC.String(name))
+	return strings.TrimSpace(norm.NFKC.String(folded))
+}
+
+func NormalizeTagName(name string) string {
+	segments := []string{}
+	for _, segment := range strings.Split(name, "/") {
+		if cleaned := NormalizeName(segment); cleaned != "" {
+			segments = append(segments, cleaned)
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func TagAncestors(name string) []string {
+	ancestors := []string{}
+	for index := 0; index < len(name); index++ {
+		if name[index] == '/' {
+			ancestors = append(ancestors, name[:index])
+		}
+	}
+	return ancestors
 }
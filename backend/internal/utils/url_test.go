@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "lowercases host and drops default port", input: "HTTPS://Example.COM:443/Path/", want: "https://example.com/Path"},
+		{name: "keeps non-default port", input: "http://example.com:8080/", want: "http://example.com:8080"},
+		{name: "drops fragment and empty query", input: "https://example.com/page?#section", want: "https://example.com/page"},
+		{name: "strips tracking params and sorts the rest", input: "https://example.com/a?utm_source=x&b=2&fbclid=y&a=1", want: "https://example.com/a?a=1&b=2"},
+		{name: "converts unicode host to punycode", input: "https://bücher.de/", want: "https://xn--bcher-kva.de"},
+		{name: "keeps only video params on youtube", input: "https://www.youtube.com/watch?v=abc&t=10&list=PL1&feature=share", want: "https://www.youtube.com/watch?list=PL1&v=abc"},
+		{name: "keeps only list param on youtu.be", input: "https://youtu.be/abc?si=x&list=PL1&t=5", want: "https://youtu.be/abc?list=PL1"},
+		{name: "trims surrounding whitespace", input: "  https://example.com/  ", want: "https://example.com"},
+		{name: "rejects empty input", input: "   ", wantErr: true},
+		{name: "rejects missing scheme", input: "example.com/page", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := NormalizeURL(test.input)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("NormalizeURL(%q) = %q, want error", test.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeURL(%q) returned error: %v", test.input, err)
+			}
+			if got != test.want {
+				t.Errorf("NormalizeURL(%q) = %q, want %q", test.input, got, test.want)
+			}
+		})
+	}
+}
+
+func TestResolveCanonicalURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		normalized string
+		finalURL   string
+		canonical  string
+		want       string
+	}{
+		{name: "no redirect or canonical", normalized: "https://example.com/a", want: "https://example.com/a"},
+		{name: "follows final url", normalized: "https://example.com/a", finalURL: "https://www.example.com/a/", want: "https://www.example.com/a"},
+		{name: "prefers canonical url", normalized: "https://example.com/a", finalURL: "https://example.com/a?x=1", canonical: "https://example.com/b", want: "https://example.com/b"},
+		{name: "ignores root canonical for deep page", normalized: "https://example.com/a", canonical: "https://example.com/", want: "https://example.com/a"},
+		{name: "ignores invalid canonical", normalized: "https://example.com/a", canonical: "/relative", want: "https://example.com/a"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ResolveCanonicalURL(test.normalized, test.finalURL, test.canonical); got != test.want {
+				t.Errorf("ResolveCanonicalURL() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "  Golang  ", want: "golang"},
+		{input: "Straße", want: "strasse"},
+		{input: "ＧＯ", want: "go"},
+		{input: "Ⅻ", want: "xii"},
+		{input: "ﬁle", want: "file"},
+		{input: "café", want: "café"},
+		{input: "cafe\u0301", want: "café"},
+		{input: "   ", want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			if got := NormalizeName(test.input); got != test.want {
+				t.Errorf("NormalizeName(%q) = %q, want %q", test.input, got, test.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeTagName(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "Go", want: "go"},
+		{input: " Dev / Go ", want: "dev/go"},
+		{input: "/dev//go/", want: "dev/go"},
+		{input: "Dev/ＧＯ/Straße", want: "dev/go/strasse"},
+		{input: " / ", want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			if got := NormalizeTagName(test.input); got != test.want {
+				t.Errorf("NormalizeTagName(%q) = %q, want %q", test.input, got, test.want)
+			}
+		})
+	}
+}
+
+func TestTagAncestors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{input: "go", want: []string{}},
+		{input: "dev/go", want: []string{"dev"}},
+		{input: "dev/go/generics", want: []string{"dev", "dev/go"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			if got := TagAncestors(test.input); !slices.Equal(got, test.want) {
+				t.Errorf("TagAncestors(%q) = %v, want %v", test.input, got, test.want)
+			}
+		})
+	}
+}
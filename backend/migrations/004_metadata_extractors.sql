@@ -0,0 +1,3 @@
+ALTER TABLE page_metadata ADD COLUMN IF NOT EXISTS category TEXT NOT NULL DEFAULT '';
+ALTER TABLE page_metadata ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE page_metadata ADD COLUMN IF NOT EXISTS properties JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,6 @@
+ALTER TABLE page_metadata ADD COLUMN IF NOT EXISTS final_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE page_metadata ADD COLUMN IF NOT EXISTS canonical_url TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE bookmarks ADD COLUMN IF NOT EXISTS canonical_url TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_bookmarks_canonical_url ON bookmarks(canonical_url);
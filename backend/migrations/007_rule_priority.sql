@@ -0,0 +1,17 @@
+ALTER TABLE rules ADD COLUMN IF NOT EXISTS priority INTEGER;
+ALTER TABLE rules ADD COLUMN IF NOT EXISTS enabled BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE rules ADD COLUMN IF NOT EXISTS stop_processing BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE rules
+SET priority = ranked.position
+FROM (
+    SELECT id, (SELECT COALESCE(MAX(priority), 0) FROM rules) + ROW_NUMBER() OVER (ORDER BY created_at ASC) AS position
+    FROM rules
+    WHERE priority IS NULL
+) ranked
+WHERE rules.id = ranked.id;
+
+ALTER TABLE rules ALTER COLUMN priority SET DEFAULT 0;
+ALTER TABLE rules ALTER COLUMN priority SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_rules_priority ON rules(priority, created_at);
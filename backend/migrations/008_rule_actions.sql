@@ -0,0 +1,5 @@
+ALTER TABLE rules ADD COLUMN IF NOT EXISTS actions JSONB;
+
+ALTER TABLE bookmarks ADD COLUMN IF NOT EXISTS read_later BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_bookmarks_read_later ON bookmarks(read_later) WHERE read_later;
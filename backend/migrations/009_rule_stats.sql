@@ -0,0 +1,12 @@
+ALTER TABLE rules ADD COLUMN IF NOT EXISTS match_count BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE rules ADD COLUMN IF NOT EXISTS last_matched_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS rule_matches (
+    rule_id UUID NOT NULL REFERENCES rules(id) ON DELETE CASCADE,
+    bookmark_id UUID NOT NULL REFERENCES bookmarks(id) ON DELETE CASCADE,
+    source TEXT NOT NULL DEFAULT '',
+    matched_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (rule_id, bookmark_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_rule_matches_rule_matched_at ON rule_matches(rule_id, matched_at DESC);
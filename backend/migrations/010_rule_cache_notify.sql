@@ -0,0 +1,28 @@
+CREATE OR REPLACE FUNCTION notify_rules_changed() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('rules_changed', TG_TABLE_NAME);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS rules_changed_write ON rules;
+CREATE TRIGGER rules_changed_write
+    AFTER INSERT OR DELETE OR UPDATE OF name, host_prefix, url_prefix, path_prefix, title_contains,
+        conditions, actions, priority, enabled, stop_processing, category_id
+    ON rules
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_rules_changed();
+
+DROP TRIGGER IF EXISTS rules_changed_tags ON rule_tags;
+CREATE TRIGGER rules_changed_tags
+    AFTER INSERT OR DELETE OR UPDATE ON rule_tags
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_rules_changed();
+
+DROP TRIGGER IF EXISTS rules_changed_tag_names ON tags;
+CREATE TRIGGER rules_changed_tag_names
+    AFTER UPDATE OF name ON tags
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_rules_changed();
+
+DROP TRIGGER IF EXISTS rules_changed_category_names ON categories;
+CREATE TRIGGER rules_changed_category_names
+    AFTER UPDATE OF name ON categories
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_rules_changed();
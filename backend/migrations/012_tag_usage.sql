@@ -0,0 +1,25 @@
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMPTZ;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMPTZ;
+
+UPDATE tags t
+SET last_used_at = usage.last_used_at
+FROM (
+    SELECT bt.tag_id, MAX(b.updated_at) AS last_used_at
+    FROM bookmark_tags bt
+    JOIN bookmarks b ON b.id = bt.bookmark_id
+    GROUP BY bt.tag_id
+) usage
+WHERE usage.tag_id = t.id AND t.last_used_at IS NULL;
+
+UPDATE categories c
+SET last_used_at = usage.last_used_at
+FROM (
+    SELECT category_id, MAX(updated_at) AS last_used_at
+    FROM bookmarks
+    WHERE category_id IS NOT NULL
+    GROUP BY category_id
+) usage
+WHERE usage.category_id = c.id AND c.last_used_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_bookmark_tags_tag ON bookmark_tags(tag_id);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_category_id ON bookmarks(category_id);
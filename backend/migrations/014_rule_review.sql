@@ -0,0 +1 @@
+ALTER TABLE rules ADD COLUMN IF NOT EXISTS review_note TEXT NOT NULL DEFAULT '';
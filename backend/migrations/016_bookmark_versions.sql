@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS bookmark_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    bookmark_id UUID NOT NULL REFERENCES bookmarks(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    source TEXT NOT NULL DEFAULT '',
+    before JSONB,
+    after JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (bookmark_id, version)
+);
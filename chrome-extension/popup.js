@@ -8,6 +8,7 @@ const tagSuggestions = document.getElementById("tag-suggestions");
 const metadataStatus = document.getElementById("metadata-status");
 const status = document.getElementById("status");
 const submitButton = document.getElementById("submit");
+const saveAllTabsButton = document.getElementById("save-all-tabs");
 const settingsButton = document.getElementById("open-settings");
 const form = document.getElementById("bookmark-form");
 
@@ -56,14 +57,31 @@ const showSuggestions = (container, items, highlightIndex, onSelect) => {
     const button = document.createElement("button");
     button.type = "button";
     button.className = `suggestion-item${index === highlightIndex ? " active" : ""}`;
-    button.textContent = item;
+    button.dataset.value = item.value;
+    if (item.color) {
+      const swatch = document.createElement("span");
+      swatch.className = "suggestion-swatch";
+      swatch.style.backgroundColor = item.color;
+      button.appendChild(swatch);
+    }
+    button.appendChild(document.createTextNode(item.label));
+    if (item.description) {
+      button.title = item.description;
+    }
     button.addEventListener("mousedown", (event) => event.preventDefault());
-    button.addEventListener("click", () => onSelect(item));
+    button.addEventListener("click", () => onSelect(item.value));
     container.appendChild(button);
   });
   container.classList.toggle("visible", items.length > 0);
 };
 
+const toSuggestion = (item) => ({
+  value: item.name,
+  label: `${item.icon ? `${item.icon} ` : ""}${item.displayName || item.name}`,
+  color: item.color || "",
+  description: item.description || ""
+});
+
 const hideSuggestions = (container) => {
   container.classList.remove("visible");
   container.innerHTML = "";
@@ -103,7 +121,7 @@ const updateBlockedState = () => {
 const loadCategories = async () => {
   try {
     const categories = await fetchJson("/categories");
-    cachedCategories = categories.map((category) => category.name);
+    cachedCategories = categories.map(toSuggestion);
   } catch (error) {
     metadataStatus.textContent = "Failed to load categories.";
   }
@@ -112,7 +130,7 @@ const loadCategories = async () => {
 const loadTags = async () => {
   try {
     const tags = await fetchJson("/tags");
-    cachedTags = tags.map((tag) => tag.name);
+    cachedTags = tags.map(toSuggestion);
   } catch (error) {
     metadataStatus.textContent = "Failed to load tag suggestions.";
   }
@@ -136,6 +154,11 @@ const loadMetadata = async (url) => {
       descriptionInput.value = data.description;
     }
 
+    if (data.rejected) {
+      metadataStatus.textContent = `Blocked by rule "${data.rejectedBy}"${data.rejectReason ? `: ${data.rejectReason}` : ""}.`;
+      return;
+    }
+
     if (data.found) {
       if (!tagsEdited && data.tags) {
         tagsInput.value = data.tags.map((tag) => tag.name).join(", ");
@@ -208,7 +231,7 @@ const renderCategorySuggestions = () => {
   const term = categoryInput.value.trim().toLowerCase();
   categoryHighlight = 0;
   const matches = cachedCategories
-    .filter((item) => item.toLowerCase().includes(term))
+    .filter((item) => item.value.includes(term) || item.label.toLowerCase().includes(term))
     .slice(0, 6);
 
   showSuggestions(categorySuggestions, matches, categoryHighlight, (selection) => {
@@ -249,7 +272,7 @@ categoryInput.addEventListener("keydown", (event) => {
     event.preventDefault();
     const selection = items[categoryHighlight];
     if (selection) {
-      categoryInput.value = selection.textContent;
+      categoryInput.value = selection.dataset.value;
       hideSuggestions(categorySuggestions);
     }
   }
@@ -270,8 +293,8 @@ tagsInput.addEventListener("input", () => {
 
   const existing = normalizeTags(tagsInput.value).map((tag) => tag.toLowerCase());
   const matches = cachedTags
-    .filter((item) => item.toLowerCase().includes(token))
-    .filter((item) => !existing.includes(item.toLowerCase()))
+    .filter((item) => item.value.includes(token) || item.label.toLowerCase().includes(token))
+    .filter((item) => !existing.includes(item.value))
     .slice(0, 6);
 
   showSuggestions(tagSuggestions, matches, tagHighlight, (selection) => {
@@ -305,7 +328,7 @@ tagsInput.addEventListener("keydown", (event) => {
     event.preventDefault();
     const selection = items[tagHighlight];
     if (selection) {
-      tagsInput.value = replaceCurrentTag(tagsInput.value, selection.textContent);
+      tagsInput.value = replaceCurrentTag(tagsInput.value, selection.dataset.value);
       tagsEdited = true;
       hideSuggestions(tagSuggestions);
     }
@@ -335,7 +358,7 @@ form.addEventListener("submit", async (event) => {
   status.classList.remove("error");
 
   try {
-    await fetchJson("/bookmarks", {
+    const saved = await fetchJson("/bookmarks", {
       method: "POST",
       body: JSON.stringify({
         url,
@@ -346,7 +369,7 @@ form.addEventListener("submit", async (event) => {
       })
     });
 
-    status.textContent = "Saved successfully.";
+    status.textContent = saved?.restoredFromTrash ? "Restored from the trash and saved." : "Saved successfully.";
   } catch (error) {
     status.textContent = error instanceof Error ? error.message : "Save failed.";
     status.classList.add("error");
@@ -355,4 +378,39 @@ form.addEventListener("submit", async (event) => {
   }
 });
 
+saveAllTabsButton.addEventListener("click", async () => {
+  const category = categoryInput.value.trim();
+  const tags = normalizeTags(tagsInput.value);
+  const tabs = await queryTabs({ currentWindow: true });
+  const items = tabs
+    .filter((tab) => tab.url && /^https?:/.test(tab.url))
+    .filter((tab) => !matchesBlacklist(tab.url, currentBlacklist))
+    .map((tab) => ({ url: tab.url, title: tab.title || "", category, tags }));
+
+  if (items.length === 0) {
+    status.textContent = "No tabs to save.";
+    status.classList.add("error");
+    return;
+  }
+
+  saveAllTabsButton.disabled = true;
+  status.textContent = `Saving ${items.length} tabs...`;
+  status.classList.remove("error");
+
+  try {
+    const report = await fetchJson("/bookmarks/batch", {
+      method: "POST",
+      body: JSON.stringify(items)
+    });
+    const skipped = report.rejected + report.failed;
+    status.textContent = `Saved ${report.created}, merged ${report.merged}${skipped ? `, skipped ${skipped}` : ""}.`;
+    status.classList.toggle("error", skipped > 0);
+  } catch (error) {
+    status.textContent = error instanceof Error ? error.message : "Save failed.";
+    status.classList.add("error");
+  } finally {
+    saveAllTabsButton.disabled = false;
+  }
+});
+
 init();
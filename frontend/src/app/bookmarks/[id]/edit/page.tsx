@@ -4,6 +4,7 @@ import { useEffect, useState } from "react";
 import { ArrowLeft, Trash2 } from "lucide-react";
 import { useParams, useRouter } from "next/navigation";
 import { BookmarkForm } from "@/components/bookmark-form";
+import { BookmarkHistory } from "@/components/bookmark-history";
 import { PageHeader } from "@/components/page-header";
 import { Button } from "@/components/ui/button";
 import {
@@ -63,6 +64,7 @@ export default function EditBookmarkPage() {
       {error ? <p className="text-sm text-destructive">{error}</p> : null}
       {bookmark ? (
         <BookmarkForm
+          key={bookmark.updatedAt}
           submitLabel="Save changes"
           initialData={bookmark}
           onSuccess={() => router.push("/")}
@@ -78,7 +80,7 @@ export default function EditBookmarkPage() {
                 <AlertDialogHeader>
                   <AlertDialogTitle>Delete bookmark</AlertDialogTitle>
                   <AlertDialogDescription>
-                    Delete “{bookmark?.title}”? This action cannot be undone.
+                    Move “{bookmark?.title}” to the trash? You can restore it from the Trash page.
                   </AlertDialogDescription>
                 </AlertDialogHeader>
                 <AlertDialogFooter>
@@ -94,6 +96,9 @@ export default function EditBookmarkPage() {
       ) : (
         <p className="text-sm text-muted-foreground">Loading...</p>
       )}
+      {bookmark ? (
+        <BookmarkHistory bookmarkId={bookmark.id} refreshKey={bookmark.updatedAt} onRestored={setBookmark} />
+      ) : null}
     </div>
   );
 }
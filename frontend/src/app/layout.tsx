@@ -1,7 +1,7 @@
 import type { Metadata } from "next";
 import "./globals.css";
 import Link from "next/link";
-import { BookMarked, Folder, Settings, Tags, Upload } from "lucide-react";
+import { BookMarked, Folder, Settings, Tags, Trash2, Upload } from "lucide-react";
 import { cn } from "@/lib/utils";
 
 export const metadata: Metadata = {
@@ -14,6 +14,7 @@ const navItems = [
   { href: "/bookmarks/new", label: "Add", icon: Upload },
   { href: "/manage/categories", label: "Categories", icon: Folder },
   { href: "/manage/tags", label: "Tags", icon: Tags },
+  { href: "/trash", label: "Trash", icon: Trash2 },
   { href: "/settings", label: "Settings", icon: Settings }
 ];
 
@@ -2,8 +2,8 @@
 
 import { useCallback } from "react";
 import { ManageNameList } from "@/components/manage-name-list";
-import { fetchJson } from "@/lib/api";
-import type { Category } from "@/lib/types";
+import { deleteQuery, fetchJson } from "@/lib/api";
+import type { Category, DeleteImpact, OrphanCleanupResponse } from "@/lib/types";
 
 export default function CategoriesPage() {
   const fetchCategories = useCallback(() => fetchJson<Category[]>("/categories"), []);
@@ -28,8 +28,26 @@ export default function CategoriesPage() {
     []
   );
 
-  const deleteCategory = useCallback(async (id: string) => {
-    await fetchJson(`/categories/${id}`, { method: "DELETE" });
+  const deleteCategory = useCallback(async (id: string, reassignTo?: string) => {
+    await fetchJson(`/categories/${id}${deleteQuery(reassignTo)}`, { method: "DELETE" });
+  }, []);
+
+  const previewCategoryDelete = useCallback(
+    (id: string, reassignTo?: string) =>
+      fetchJson<DeleteImpact>(`/categories/${id}${deleteQuery(reassignTo, true)}`, { method: "DELETE" }),
+    []
+  );
+
+  const mergeCategory = useCallback(async (id: string, targetId: string) => {
+    await fetchJson(`/categories/${id}/merge`, {
+      method: "POST",
+      body: JSON.stringify({ targetId })
+    });
+  }, []);
+
+  const deleteUnusedCategories = useCallback(async () => {
+    const result = await fetchJson<OrphanCleanupResponse>("/categories/orphans", { method: "DELETE" });
+    return result.deleted;
   }, []);
 
   return (
@@ -44,6 +62,9 @@ export default function CategoriesPage() {
       createItem={createCategory}
       renameItem={renameCategory}
       deleteItem={deleteCategory}
+      previewDelete={previewCategoryDelete}
+      mergeItem={mergeCategory}
+      deleteOrphans={deleteUnusedCategories}
     />
   );
 }
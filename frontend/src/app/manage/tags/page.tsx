@@ -2,8 +2,8 @@
 
 import { useCallback } from "react";
 import { ManageNameList } from "@/components/manage-name-list";
-import { fetchJson } from "@/lib/api";
-import type { Tag } from "@/lib/types";
+import { deleteQuery, fetchJson } from "@/lib/api";
+import type { Tag, DeleteImpact, OrphanCleanupResponse } from "@/lib/types";
 
 export default function TagsPage() {
   const fetchTags = useCallback(() => fetchJson<Tag[]>("/tags"), []);
@@ -28,8 +28,26 @@ export default function TagsPage() {
     []
   );
 
-  const deleteTag = useCallback(async (id: string) => {
-    await fetchJson(`/tags/${id}`, { method: "DELETE" });
+  const deleteTag = useCallback(async (id: string, reassignTo?: string) => {
+    await fetchJson(`/tags/${id}${deleteQuery(reassignTo)}`, { method: "DELETE" });
+  }, []);
+
+  const previewTagDelete = useCallback(
+    (id: string, reassignTo?: string) =>
+      fetchJson<DeleteImpact>(`/tags/${id}${deleteQuery(reassignTo, true)}`, { method: "DELETE" }),
+    []
+  );
+
+  const mergeTag = useCallback(async (id: string, targetId: string) => {
+    await fetchJson(`/tags/${id}/merge`, {
+      method: "POST",
+      body: JSON.stringify({ targetId })
+    });
+  }, []);
+
+  const deleteUnusedTags = useCallback(async () => {
+    const result = await fetchJson<OrphanCleanupResponse>("/tags/orphans", { method: "DELETE" });
+    return result.deleted;
   }, []);
 
   return (
@@ -44,6 +62,9 @@ export default function TagsPage() {
       createItem={createTag}
       renameItem={renameTag}
       deleteItem={deleteTag}
+      previewDelete={previewTagDelete}
+      mergeItem={mergeTag}
+      deleteOrphans={deleteUnusedTags}
     />
   );
 }
@@ -4,6 +4,7 @@ import { useEffect, useState } from "react";
 import { PageHeader } from "@/components/page-header";
 import { SectionCard } from "@/components/section-card";
 import { TagInput } from "@/components/tag-input";
+import { Badge } from "@/components/ui/badge";
 import { Button } from "@/components/ui/button";
 import {
   AlertDialog,
@@ -18,7 +19,7 @@ import {
 } from "@/components/ui/alert-dialog";
 import { Input } from "@/components/ui/input";
 import { fetchJson } from "@/lib/api";
-import type { Category, Rule } from "@/lib/types";
+import type { Category, Rule, RuleActions, RuleCondition, RuleSuggestion } from "@/lib/types";
 
 interface RuleFormState {
   name: string;
@@ -26,6 +27,9 @@ interface RuleFormState {
   urlPrefix: string;
   pathPrefix: string;
   titleContains: string;
+  conditions?: RuleCondition | null;
+  stopProcessing?: boolean;
+  actions?: RuleActions | null;
   category: string;
   tags: string[];
 }
@@ -42,18 +46,21 @@ const emptyRule: RuleFormState = {
 
 export default function SettingsPage() {
   const [rules, setRules] = useState<Rule[]>([]);
+  const [suggestions, setSuggestions] = useState<RuleSuggestion[]>([]);
   const [categories, setCategories] = useState<Category[]>([]);
   const [newRule, setNewRule] = useState<RuleFormState>(emptyRule);
   const [message, setMessage] = useState<string | null>(null);
 
   const loadData = async () => {
     try {
-      const [ruleData, categoryData] = await Promise.all([
+      const [ruleData, categoryData, suggestionData] = await Promise.all([
         fetchJson<Rule[]>("/rules"),
-        fetchJson<Category[]>("/categories")
+        fetchJson<Category[]>("/categories"),
+        fetchJson<RuleSuggestion[]>("/rules/suggestions")
       ]);
       setRules(ruleData);
       setCategories(categoryData);
+      setSuggestions(suggestionData);
     } catch (error) {
       setMessage("Failed to load settings data.");
     }
@@ -77,6 +84,19 @@ export default function SettingsPage() {
     }
   };
 
+  const acceptSuggestion = async (suggestion: RuleSuggestion) => {
+    setMessage(null);
+    try {
+      await fetchJson<Rule>("/rules", {
+        method: "POST",
+        body: JSON.stringify(suggestion.rule)
+      });
+      loadData();
+    } catch (error) {
+      setMessage("Failed to create rule.");
+    }
+  };
+
   const updateRule = async (id: string, payload: RuleFormState) => {
     setMessage(null);
     try {
@@ -140,6 +160,33 @@ export default function SettingsPage() {
         </div>
       </SectionCard>
 
+      {suggestions.length > 0 ? (
+        <SectionCard title="Suggested rules">
+          <div className="space-y-3">
+            {suggestions.map((suggestion) => (
+              <div
+                key={suggestion.rule.name}
+                className="flex flex-wrap items-center justify-between gap-3 rounded-md border p-3"
+              >
+                <div className="space-y-1 text-sm">
+                  <div className="font-semibold">{suggestion.rule.name}</div>
+                  <div className="text-muted-foreground">
+                    {suggestion.category ? `Category “${suggestion.category}”` : "No category"}
+                    {suggestion.tags.length > 0 ? ` · tags ${suggestion.tags.map((tag) => tag.name).join(", ")}` : ""}
+                  </div>
+                  <div className="text-xs text-muted-foreground">
+                    {suggestion.support} bookmarks · {Math.round(suggestion.confidence * 100)}% confidence
+                  </div>
+                </div>
+                <Button type="button" variant="outline" onClick={() => acceptSuggestion(suggestion)}>
+                  Create rule
+                </Button>
+              </div>
+            ))}
+          </div>
+        </SectionCard>
+      ) : null}
+
       <SectionCard title="Danger zone">
         <div className="space-y-4">
           <p className="text-sm text-muted-foreground">
@@ -263,6 +310,9 @@ function RuleRow({
     urlPrefix: rule.urlPrefix,
     pathPrefix: rule.pathPrefix,
     titleContains: rule.titleContains,
+    conditions: rule.conditions,
+    stopProcessing: rule.stopProcessing,
+    actions: rule.actions,
     category: rule.categoryName || "",
     tags: rule.tags.map((tag) => tag.name)
   });
@@ -275,6 +325,9 @@ function RuleRow({
       urlPrefix: rule.urlPrefix,
       pathPrefix: rule.pathPrefix,
       titleContains: rule.titleContains,
+      conditions: rule.conditions,
+      stopProcessing: rule.stopProcessing,
+      actions: rule.actions,
       category: rule.categoryName || "",
       tags: rule.tags.map((tag) => tag.name)
     });
@@ -282,7 +335,18 @@ function RuleRow({
 
   return (
     <div className="rounded-md border p-4 space-y-4">
-      <div className="text-sm font-semibold">{rule.name}</div>
+      <div className="flex flex-wrap items-baseline justify-between gap-2">
+        <div className="flex items-center gap-2 text-sm font-semibold">
+          {rule.name}
+          {rule.reviewNote ? <Badge variant="outline" className="border-destructive text-destructive">Needs review: {rule.reviewNote}</Badge> : null}
+        </div>
+        {rule.stats ? (
+          <div className="text-xs text-muted-foreground">
+            {rule.stats.matchCount} matches · {rule.stats.affectedBookmarks} bookmarks
+            {rule.stats.lastMatchedAt ? ` · last ${new Date(rule.stats.lastMatchedAt).toLocaleDateString()}` : " · never matched"}
+          </div>
+        ) : null}
+      </div>
       <form
         className="grid gap-3 md:grid-cols-2"
         onSubmit={(event) => {
@@ -0,0 +1,180 @@
+"use client";
+
+import { useEffect, useState } from "react";
+import { Trash2 } from "lucide-react";
+import { PageHeader } from "@/components/page-header";
+import { Pagination } from "@/components/pagination";
+import { SectionCard } from "@/components/section-card";
+import { Button } from "@/components/ui/button";
+import {
+  AlertDialog,
+  AlertDialogAction,
+  AlertDialogCancel,
+  AlertDialogContent,
+  AlertDialogDescription,
+  AlertDialogFooter,
+  AlertDialogHeader,
+  AlertDialogTitle
+} from "@/components/ui/alert-dialog";
+import { fetchJson } from "@/lib/api";
+import type { Bookmark, BookmarkListResponse } from "@/lib/types";
+
+export default function TrashPage() {
+  const [data, setData] = useState<BookmarkListResponse | null>(null);
+  const [message, setMessage] = useState<string | null>(null);
+  const [confirmBookmark, setConfirmBookmark] = useState<Bookmark | null>(null);
+  const [confirmEmpty, setConfirmEmpty] = useState(false);
+
+  const loadData = async (pageNumber = 1) => {
+    try {
+      const result = await fetchJson<BookmarkListResponse>(`/trash?page=${pageNumber}&page_size=20`);
+      setData(result);
+    } catch (error) {
+      setData(null);
+      setMessage(error instanceof Error ? error.message : "Failed to load trash");
+    }
+  };
+
+  useEffect(() => {
+    loadData(1);
+  }, []);
+
+  const currentPage = data?.pagination.page ?? 1;
+
+  const handleRestore = async (bookmark: Bookmark) => {
+    setMessage(null);
+    try {
+      await fetchJson(`/trash/${bookmark.id}/restore`, { method: "POST" });
+      setMessage(`Restored “${bookmark.title}”.`);
+      loadData(currentPage);
+    } catch (error) {
+      setMessage(error instanceof Error ? error.message : "Restore failed");
+    }
+  };
+
+  const handleDelete = async (bookmark: Bookmark) => {
+    setMessage(null);
+    try {
+      await fetchJson(`/trash/${bookmark.id}`, { method: "DELETE" });
+      loadData(currentPage);
+    } catch (error) {
+      setMessage(error instanceof Error ? error.message : "Delete failed");
+    }
+  };
+
+  const handleEmpty = async () => {
+    setMessage(null);
+    try {
+      const result = await fetchJson<{ deleted: number }>("/trash", { method: "DELETE" });
+      setMessage(`Deleted ${result.deleted} bookmarks permanently.`);
+      loadData(1);
+    } catch (error) {
+      setMessage(error instanceof Error ? error.message : "Empty trash failed");
+    }
+  };
+
+  return (
+    <div className="space-y-6">
+      <PageHeader
+        title="Trash"
+        description="Removed bookmarks stay here until the retention period ends."
+        actions={
+          <Button
+            variant="outline"
+            className="gap-2"
+            disabled={!data || data.items.length === 0}
+            onClick={() => setConfirmEmpty(true)}
+          >
+            <Trash2 className="h-4 w-4" />
+            Empty trash
+          </Button>
+        }
+      />
+
+      <SectionCard title="Removed bookmarks">
+        {message ? <p className="mb-4 text-sm text-muted-foreground">{message}</p> : null}
+        {data && data.items.length > 0 ? (
+          <div className="space-y-4">
+            {data.items.map((bookmark) => (
+              <div key={bookmark.id} className="space-y-1 rounded-md border px-4 py-3 min-w-0">
+                <div className="truncate text-sm font-semibold">{bookmark.title}</div>
+                <div className="truncate text-xs text-muted-foreground">{bookmark.url}</div>
+                <div className="flex flex-wrap items-center gap-3 text-xs text-muted-foreground">
+                  {bookmark.deletedAt ? (
+                    <span>Removed {new Date(bookmark.deletedAt).toLocaleDateString()}</span>
+                  ) : null}
+                  <Button
+                    type="button"
+                    variant="ghost"
+                    size="sm"
+                    className="h-auto p-0 text-xs text-primary"
+                    onClick={() => handleRestore(bookmark)}
+                  >
+                    Restore
+                  </Button>
+                  <Button
+                    type="button"
+                    variant="ghost"
+                    size="sm"
+                    className="h-auto p-0 text-xs text-destructive hover:text-destructive"
+                    onClick={() => setConfirmBookmark(bookmark)}
+                  >
+                    Delete forever
+                  </Button>
+                </div>
+              </div>
+            ))}
+            <Pagination
+              page={data.pagination.page}
+              pageSize={data.pagination.pageSize}
+              total={data.pagination.total}
+              onPageChange={(next) => loadData(next)}
+            />
+          </div>
+        ) : (
+          <p className="text-sm text-muted-foreground">Trash is empty.</p>
+        )}
+      </SectionCard>
+
+      <AlertDialog
+        open={Boolean(confirmBookmark)}
+        onOpenChange={(open) => !open && setConfirmBookmark(null)}
+      >
+        <AlertDialogContent>
+          <AlertDialogHeader>
+            <AlertDialogTitle>Delete bookmark forever</AlertDialogTitle>
+            <AlertDialogDescription>
+              Permanently delete “{confirmBookmark?.title}”? This action cannot be undone.
+            </AlertDialogDescription>
+          </AlertDialogHeader>
+          <AlertDialogFooter>
+            <AlertDialogCancel>Cancel</AlertDialogCancel>
+            <AlertDialogAction
+              variant="destructive"
+              onClick={() => confirmBookmark && handleDelete(confirmBookmark)}
+            >
+              Delete forever
+            </AlertDialogAction>
+          </AlertDialogFooter>
+        </AlertDialogContent>
+      </AlertDialog>
+
+      <AlertDialog open={confirmEmpty} onOpenChange={setConfirmEmpty}>
+        <AlertDialogContent>
+          <AlertDialogHeader>
+            <AlertDialogTitle>Empty trash</AlertDialogTitle>
+            <AlertDialogDescription>
+              Permanently delete every bookmark in the trash? This action cannot be undone.
+            </AlertDialogDescription>
+          </AlertDialogHeader>
+          <AlertDialogFooter>
+            <AlertDialogCancel>Cancel</AlertDialogCancel>
+            <AlertDialogAction variant="destructive" onClick={handleEmpty}>
+              Empty trash
+            </AlertDialogAction>
+          </AlertDialogFooter>
+        </AlertDialogContent>
+      </AlertDialog>
+    </div>
+  );
+}
@@ -0,0 +1,112 @@
+"use client";
+
+import { useEffect, useState } from "react";
+import { RotateCcw } from "lucide-react";
+import { SectionCard } from "@/components/section-card";
+import { Badge } from "@/components/ui/badge";
+import { Button } from "@/components/ui/button";
+import { fetchJson } from "@/lib/api";
+import type { Bookmark, BookmarkSnapshot, BookmarkVersion } from "@/lib/types";
+
+interface BookmarkHistoryProps {
+  bookmarkId: string;
+  refreshKey: string;
+  onRestored: (bookmark: Bookmark) => void;
+}
+
+const formatValue = (snapshot: BookmarkSnapshot | null, field: string) => {
+  if (!snapshot) {
+    return "—";
+  }
+  const value = snapshot[field as keyof BookmarkSnapshot];
+  if (Array.isArray(value)) {
+    return value.length > 0 ? value.join(", ") : "—";
+  }
+  if (typeof value === "boolean") {
+    return value ? "yes" : "no";
+  }
+  return value || "—";
+};
+
+export function BookmarkHistory({ bookmarkId, refreshKey, onRestored }: BookmarkHistoryProps) {
+  const [versions, setVersions] = useState<BookmarkVersion[]>([]);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    fetchJson<BookmarkVersion[]>(`/bookmarks/${bookmarkId}/history`)
+      .then(setVersions)
+      .catch((err) => setError(err instanceof Error ? err.message : "Failed to load history"));
+  }, [bookmarkId, refreshKey]);
+
+  const handleRestore = async (version: number, state: "before" | "after") => {
+    setError(null);
+    try {
+      const bookmark = await fetchJson<Bookmark>(`/bookmarks/${bookmarkId}/restore/${version}?state=${state}`, {
+        method: "POST"
+      });
+      onRestored(bookmark);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Restore failed");
+    }
+  };
+
+  return (
+    <SectionCard title="History">
+      {error ? <p className="mb-4 text-sm text-destructive">{error}</p> : null}
+      {versions.length === 0 ? (
+        <p className="text-sm text-muted-foreground">No recorded changes yet.</p>
+      ) : (
+        <div className="space-y-3">
+          {versions.map((version, index) => (
+            <div key={version.version} className="space-y-2 rounded-md border px-4 py-3">
+              <div className="flex flex-wrap items-center gap-2 text-xs text-muted-foreground">
+                <span className="font-semibold text-foreground">v{version.version}</span>
+                <Badge variant="secondary">{version.source}</Badge>
+                <span>{new Date(version.createdAt).toLocaleString()}</span>
+                <div className="ml-auto flex gap-3">
+                  {version.before ? (
+                    <Button
+                      type="button"
+                      variant="ghost"
+                      size="sm"
+                      className="h-auto gap-1 p-0 text-xs text-primary"
+                      onClick={() => handleRestore(version.version, "before")}
+                    >
+                      <RotateCcw className="h-3 w-3" />
+                      Restore state before
+                    </Button>
+                  ) : null}
+                  {index > 0 ? (
+                    <Button
+                      type="button"
+                      variant="ghost"
+                      size="sm"
+                      className="h-auto gap-1 p-0 text-xs text-primary"
+                      onClick={() => handleRestore(version.version, "after")}
+                    >
+                      <RotateCcw className="h-3 w-3" />
+                      Restore this version
+                    </Button>
+                  ) : null}
+                </div>
+              </div>
+              {version.before ? (
+                <ul className="space-y-1 text-xs">
+                  {version.changed.map((field) => (
+                    <li key={field} className="break-words">
+                      <span className="font-medium">{field}</span>:{" "}
+                      <span className="text-muted-foreground line-through">{formatValue(version.before, field)}</span>{" "}
+                      → {formatValue(version.after, field)}
+                    </li>
+                  ))}
+                </ul>
+              ) : (
+                <p className="text-xs text-muted-foreground">Created “{version.after.title}”.</p>
+              )}
+            </div>
+          ))}
+        </div>
+      )}
+    </SectionCard>
+  );
+}
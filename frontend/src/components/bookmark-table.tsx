@@ -63,8 +63,10 @@ export function BookmarkTable({ items, onTagClick, onRemove }: BookmarkTableProp
                         type="button"
                         onClick={() => onTagClick(tag.name)}
                         className="text-primary hover:underline"
+                        style={tag.color ? { color: tag.color } : undefined}
+                        title={tag.description || undefined}
                       >
-                        #{tag.name}
+                        {tag.icon ? `${tag.icon} ` : ""}#{tag.displayName || tag.name}
                       </button>
                       {index < sortedTags.length - 1 ? <span className="text-muted-foreground">|</span> : null}
                     </span>
@@ -114,7 +116,7 @@ export function BookmarkTable({ items, onTagClick, onRemove }: BookmarkTableProp
           <AlertDialogHeader>
             <AlertDialogTitle>Remove bookmark</AlertDialogTitle>
             <AlertDialogDescription>
-              Remove “{confirmBookmark?.title}”? This action cannot be undone.
+              Move “{confirmBookmark?.title}” to the trash? You can restore it from the Trash page.
             </AlertDialogDescription>
           </AlertDialogHeader>
           <AlertDialogFooter>
@@ -1,9 +1,11 @@
 "use client";
 
 import { useEffect, useMemo, useRef, useState } from "react";
-import { Check, Pencil, Plus, Search, Trash2, X } from "lucide-react";
+import { Check, Eraser, Pencil, Plus, Search, Trash2, X } from "lucide-react";
 import { Button } from "@/components/ui/button";
 import { Input } from "@/components/ui/input";
+import { Select } from "@/components/ui/select";
+import type { DeleteImpact, Usage } from "@/lib/types";
 import {
   AlertDialog,
   AlertDialogAction,
@@ -26,6 +28,10 @@ interface ToastState {
 interface NameListItem {
   id: string;
   name: string;
+  displayName?: string;
+  color?: string;
+  icon?: string;
+  usage?: Usage;
 }
 
 interface ManageNameListProps<T extends NameListItem> {
@@ -38,7 +44,15 @@ interface ManageNameListProps<T extends NameListItem> {
   fetchItems: () => Promise<T[]>;
   createItem: (name: string) => Promise<void>;
   renameItem: (id: string, name: string) => Promise<void>;
-  deleteItem: (id: string) => Promise<void>;
+  deleteItem: (id: string, reassignTo?: string) => Promise<void>;
+  previewDelete?: (id: string, reassignTo?: string) => Promise<DeleteImpact>;
+  mergeItem?: (id: string, targetId: string) => Promise<void>;
+  deleteOrphans?: () => Promise<number>;
+}
+
+interface MergeState<T> {
+  source: T;
+  target: T;
 }
 
 export function ManageNameList<T extends NameListItem>({
@@ -51,7 +65,10 @@ export function ManageNameList<T extends NameListItem>({
   fetchItems,
   createItem,
   renameItem,
-  deleteItem
+  deleteItem,
+  mergeItem,
+  deleteOrphans,
+  previewDelete
 }: ManageNameListProps<T>) {
   const [items, setItems] = useState<T[]>([]);
   const [newName, setNewName] = useState("");
@@ -64,6 +81,10 @@ export function ManageNameList<T extends NameListItem>({
   const [loadError, setLoadError] = useState<string | null>(null);
   const [isLoading, setIsLoading] = useState(false);
   const [confirmItem, setConfirmItem] = useState<T | null>(null);
+  const [reassignTo, setReassignTo] = useState("");
+  const [deleteImpact, setDeleteImpact] = useState<DeleteImpact | null>(null);
+  const [mergeState, setMergeState] = useState<MergeState<T> | null>(null);
+  const [confirmOrphans, setConfirmOrphans] = useState(false);
   const [isSaving, setIsSaving] = useState(false);
 
   const newInputRef = useRef<HTMLInputElement>(null);
@@ -103,6 +124,25 @@ export function ManageNameList<T extends NameListItem>({
   }, []);
 
 
+  useEffect(() => {
+    setDeleteImpact(null);
+    if (!confirmItem || !previewDelete) {
+      return;
+    }
+    let active = true;
+    previewDelete(confirmItem.id, reassignTo || undefined)
+      .then((impact) => active && setDeleteImpact(impact))
+      .catch(() => active && setDeleteImpact(null));
+    return () => {
+      active = false;
+    };
+  }, [confirmItem, reassignTo, previewDelete]);
+
+  const openDelete = (item: T) => {
+    setReassignTo("");
+    setConfirmItem(item);
+  };
+
   useEffect(() => {
     return () => {
       if (toastTimeout.current) {
@@ -156,7 +196,7 @@ export function ManageNameList<T extends NameListItem>({
 
   const startEdit = (item: T) => {
     setEditingId(item.id);
-    setEditingName(item.name);
+    setEditingName(item.displayName || item.name);
     setEditingError(null);
   };
 
@@ -167,6 +207,13 @@ export function ManageNameList<T extends NameListItem>({
   };
 
   const saveEdit = async (item: T) => {
+    const normalized = editingName.trim().toLowerCase();
+    const duplicate = items.find((other) => other.id !== item.id && other.name.toLowerCase() === normalized);
+    if (duplicate && mergeItem) {
+      setMergeState({ source: item, target: duplicate });
+      return;
+    }
+
     const error = validateName(editingName, item.id);
     if (error) {
       setEditingError(error);
@@ -194,7 +241,7 @@ export function ManageNameList<T extends NameListItem>({
     }
     setIsSaving(true);
     try {
-      await deleteItem(confirmItem.id);
+      await deleteItem(confirmItem.id, reassignTo || undefined);
       showToast(`Deleted ${entityLabel.toLowerCase()} "${confirmItem.name}".`, "success");
       setConfirmItem(null);
       await load();
@@ -205,6 +252,43 @@ export function ManageNameList<T extends NameListItem>({
     }
   };
 
+  const mergeConfirmed = async () => {
+    if (!mergeState || !mergeItem) {
+      return;
+    }
+    setIsSaving(true);
+    try {
+      await mergeItem(mergeState.source.id, mergeState.target.id);
+      showToast(`Merged "${mergeState.source.name}" into "${mergeState.target.name}".`, "success");
+      setMergeState(null);
+      cancelEdit();
+      await load();
+    } catch (error) {
+      showToast("Merge failed. Please try again.", "error");
+    } finally {
+      setIsSaving(false);
+    }
+  };
+
+  const orphanCount = items.filter((item) => item.usage && item.usage.bookmarkCount === 0 && item.usage.ruleCount === 0).length;
+
+  const deleteOrphansConfirmed = async () => {
+    if (!deleteOrphans) {
+      return;
+    }
+    setIsSaving(true);
+    try {
+      const deleted = await deleteOrphans();
+      showToast(`Removed ${deleted} unused ${entityPluralLabel.toLowerCase()}.`, "success");
+      setConfirmOrphans(false);
+      await load();
+    } catch (error) {
+      showToast("Cleanup failed. Please try again.", "error");
+    } finally {
+      setIsSaving(false);
+    }
+  };
+
   return (
     <div className="space-y-6">
       <div className="flex flex-wrap items-start justify-between gap-4">
@@ -212,7 +296,21 @@ export function ManageNameList<T extends NameListItem>({
           <h1 className="text-2xl font-semibold tracking-tight">{title}</h1>
           <p className="text-sm text-muted-foreground">{description}</p>
         </div>
-        <div className="text-sm text-muted-foreground">{items.length} total</div>
+        <div className="flex items-center gap-3 text-sm text-muted-foreground">
+          {items.length} total
+          {deleteOrphans ? (
+            <Button
+              type="button"
+              variant="outline"
+              size="sm"
+              onClick={() => setConfirmOrphans(true)}
+              disabled={isSaving || orphanCount === 0}
+            >
+              <Eraser className="h-4 w-4" />
+              Remove unused
+            </Button>
+          ) : null}
+        </div>
       </div>
 
       <div className="rounded-md border bg-card text-card-foreground shadow-sm">
@@ -302,7 +400,26 @@ export function ManageNameList<T extends NameListItem>({
                           />
                         </div>
                       ) : (
-                        <div className="text-sm font-medium flex-1">{item.name}</div>
+                        <div className="flex-1">
+                          <div className="flex items-center gap-2 text-sm font-medium">
+                            {item.color ? (
+                              <span className="h-2.5 w-2.5 rounded-full" style={{ backgroundColor: item.color }} />
+                            ) : null}
+                            {item.icon ? <span>{item.icon}</span> : null}
+                            <span>{item.displayName || item.name}</span>
+                            {item.displayName ? (
+                              <span className="text-xs font-normal text-muted-foreground">{item.name}</span>
+                            ) : null}
+                          </div>
+                          {item.usage ? (
+                            <div className="text-xs text-muted-foreground">
+                              {item.usage.bookmarkCount} bookmarks · {item.usage.ruleCount} rules
+                              {item.usage.lastUsedAt
+                                ? ` · last used ${new Date(item.usage.lastUsedAt).toLocaleDateString()}`
+                                : ""}
+                            </div>
+                          ) : null}
+                        </div>
                       )}
 
                       <div className="flex items-center gap-2">
@@ -339,7 +456,7 @@ export function ManageNameList<T extends NameListItem>({
                               type="button"
                               variant="ghost"
                               size="sm"
-                              onClick={() => setConfirmItem(item)}
+                              onClick={() => openDelete(item)}
                               disabled={Boolean(editingId)}
                             >
                               <Trash2 className="h-4 w-4" />
@@ -382,6 +499,31 @@ export function ManageNameList<T extends NameListItem>({
               Delete {entityLabel} “{confirmItem?.name}”? This cannot be undone.
             </AlertDialogDescription>
           </AlertDialogHeader>
+          {previewDelete ? (
+            <div className="space-y-3 text-sm">
+              <label className="block space-y-1">
+                <span className="text-xs text-muted-foreground">Reassign to</span>
+                <Select value={reassignTo} onChange={(event) => setReassignTo(event.target.value)}>
+                  <option value="">Nothing</option>
+                  {items
+                    .filter((item) => item.id !== confirmItem?.id)
+                    .map((item) => (
+                      <option key={item.id} value={item.id}>
+                        {item.displayName || item.name}
+                      </option>
+                    ))}
+                </Select>
+              </label>
+              {deleteImpact ? (
+                <p className="text-muted-foreground">
+                  {deleteImpact.bookmarks} bookmarks and {deleteImpact.rules.length} rules use it.
+                  {deleteImpact.rules.length > 0 && !reassignTo
+                    ? ` Rules that used it will be flagged for review: ${deleteImpact.rules.map((rule) => rule.name).join(", ")}.`
+                    : ""}
+                </p>
+              ) : null}
+            </div>
+          ) : null}
           <AlertDialogFooter>
             <AlertDialogCancel>Cancel</AlertDialogCancel>
             <AlertDialogAction variant="destructive" onClick={deleteConfirmed} disabled={isSaving}>
@@ -390,6 +532,41 @@ export function ManageNameList<T extends NameListItem>({
           </AlertDialogFooter>
         </AlertDialogContent>
       </AlertDialog>
+
+      <AlertDialog open={confirmOrphans} onOpenChange={(open) => !open && setConfirmOrphans(false)}>
+        <AlertDialogContent>
+          <AlertDialogHeader>
+            <AlertDialogTitle>Remove unused {entityPluralLabel.toLowerCase()}</AlertDialogTitle>
+            <AlertDialogDescription>
+              Delete every {entityLabel.toLowerCase()} that no bookmark or rule uses? This cannot be undone.
+            </AlertDialogDescription>
+          </AlertDialogHeader>
+          <AlertDialogFooter>
+            <AlertDialogCancel>Cancel</AlertDialogCancel>
+            <AlertDialogAction variant="destructive" onClick={deleteOrphansConfirmed} disabled={isSaving}>
+              Remove
+            </AlertDialogAction>
+          </AlertDialogFooter>
+        </AlertDialogContent>
+      </AlertDialog>
+
+      <AlertDialog open={Boolean(mergeState)} onOpenChange={(open) => !open && setMergeState(null)}>
+        <AlertDialogContent>
+          <AlertDialogHeader>
+            <AlertDialogTitle>Merge {entityLabel}</AlertDialogTitle>
+            <AlertDialogDescription>
+              “{mergeState?.target.name}” already exists. Merge “{mergeState?.source.name}” into it? Bookmarks and rules
+              move to “{mergeState?.target.name}” and “{mergeState?.source.name}” is deleted.
+            </AlertDialogDescription>
+          </AlertDialogHeader>
+          <AlertDialogFooter>
+            <AlertDialogCancel>Cancel</AlertDialogCancel>
+            <AlertDialogAction onClick={mergeConfirmed} disabled={isSaving}>
+              Merge
+            </AlertDialogAction>
+          </AlertDialogFooter>
+        </AlertDialogContent>
+      </AlertDialog>
     </div>
   );
 }
@@ -15,6 +15,18 @@ export async function fetchJson<T>(path: string, options?: RequestInit): Promise
   return response.json() as Promise<T>;
 }
 
+export function deleteQuery(reassignTo?: string, preview = false): string {
+  const params = new URLSearchParams();
+  if (reassignTo) {
+    params.set("reassign_to", reassignTo);
+  }
+  if (preview) {
+    params.set("preview", "true");
+  }
+  const query = params.toString();
+  return query ? `?${query}` : "";
+}
+
 export async function fetchText(path: string): Promise<string> {
   const response = await fetch(`${API_BASE_URL}${path}`);
   if (!response.ok) {
@@ -1,17 +1,51 @@
 export interface Tag {
   id: string;
   name: string;
+  displayName?: string;
+  color?: string;
+  icon?: string;
+  description?: string;
+  usage?: Usage;
+}
+
+export interface Usage {
+  bookmarkCount: number;
+  ruleCount: number;
+  lastUsedAt?: string | null;
+}
+
+export interface TagNode {
+  id?: string;
+  name: string;
+  label: string;
+  bookmarkCount: number;
+  totalCount: number;
+  children: TagNode[];
+}
+
+export interface TagAlias {
+  id: string;
+  tagId: string;
+  alias: string;
+  createdAt: string;
 }
 
 export interface Category {
   id: string;
   name: string;
+  displayName?: string;
+  color?: string;
+  icon?: string;
+  description?: string;
+  usage?: Usage;
 }
 
 export interface Bookmark {
   id: string;
   url: string;
   normalizedUrl: string;
+  canonicalUrl?: string;
+  readLater?: boolean;
   title: string;
   description: string;
   categoryId?: string | null;
@@ -19,6 +53,8 @@ export interface Bookmark {
   tags: Tag[];
   createdAt: string;
   updatedAt: string;
+  deletedAt?: string | null;
+  restoredFromTrash?: boolean;
 }
 
 export interface Pagination {
@@ -39,9 +75,128 @@ export interface Rule {
   urlPrefix: string;
   pathPrefix: string;
   titleContains: string;
+  conditions?: RuleCondition | null;
+  priority: number;
+  enabled: boolean;
+  stopProcessing: boolean;
+  actions?: RuleActions | null;
   categoryId?: string | null;
   categoryName?: string | null;
   tags: Tag[];
+  stats?: RuleStats;
+  reviewNote?: string;
   createdAt: string;
   updatedAt: string;
 }
+
+export interface RuleCondition {
+  all?: RuleCondition[];
+  any?: RuleCondition[];
+  field?: "host" | "url" | "path" | "title" | "description" | "tag";
+  op?: "prefix" | "suffix" | "contains" | "equals" | "regex";
+  value?: string;
+  negate?: boolean;
+}
+
+export interface RuleActions {
+  removeTags?: string[];
+  titlePattern?: string;
+  titleReplace?: string;
+  urlPattern?: string;
+  urlReplace?: string;
+  readLater?: boolean;
+  reject?: boolean;
+  rejectReason?: string;
+}
+
+export interface RuleSuggestion {
+  hostPrefix: string;
+  pathPrefix: string;
+  support: number;
+  confidence: number;
+  category: string;
+  categoryConfidence: number;
+  tags: { name: string; confidence: number }[];
+  rule: {
+    name: string;
+    hostPrefix: string;
+    conditions?: RuleCondition;
+    category?: string;
+    tags?: string[];
+  };
+}
+
+export interface RuleStats {
+  matchCount: number;
+  lastMatchedAt?: string | null;
+  affectedBookmarks: number;
+}
+
+export interface OrphanCleanupResponse {
+  deleted: number;
+  names: string[];
+}
+
+export interface DeleteImpact {
+  preview: boolean;
+  name: string;
+  bookmarks: number;
+  rules: { id: string; name: string }[];
+  reassignedTo?: string | null;
+  flaggedRules: number;
+}
+
+export type BulkActionType =
+  | "add_tags"
+  | "remove_tags"
+  | "set_category"
+  | "delete"
+  | "refresh_metadata"
+  | "apply_rules";
+
+export interface BulkAction {
+  type: BulkActionType;
+  tags?: string[];
+  category?: string;
+}
+
+export interface BulkReport {
+  matched: number;
+  results: { type: BulkActionType; affected: number }[];
+}
+
+export interface BatchResult {
+  index: number;
+  url: string;
+  status: "created" | "merged" | "rejected" | "failed";
+  bookmark?: Bookmark;
+  error?: string;
+  rejectedBy?: string;
+}
+
+export interface BatchReport {
+  created: number;
+  merged: number;
+  rejected: number;
+  failed: number;
+  results: BatchResult[];
+}
+
+export interface BookmarkSnapshot {
+  url: string;
+  title: string;
+  description: string;
+  category: string;
+  tags: string[];
+  readLater: boolean;
+  trashed: boolean;
+}
+
+export interface BookmarkVersion {
+  version: number;
+  source: "api" | "extension" | "import" | "rule" | "bulk" | "restore" | "refresh" | "merge";
+  before: BookmarkSnapshot | null;
+  after: BookmarkSnapshot;
+  changed: string[];
+  createdAt: string;
+}